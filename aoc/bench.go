@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// benchmark lines printed by go test for the BenchmarkPart<N>/<phase> benchmarks of
+// a day, e.g. "BenchmarkPart1/parse-8   828   1481348 ns/op   924382 B/op   9184 allocs/op"
+var benchLinePattern = regexp.MustCompile(`^BenchmarkPart(\d+)/(\w+)(?:-\d+)?\s+\d+\s+([\d.]+) ns/op(?:\s+([\d.]+) B/op\s+([\d.]+) allocs/op)?`)
+
+type benchSample struct {
+	part   int
+	phase  string
+	ns     float64
+	bytes  float64
+	allocs float64
+}
+
+// benchResult summarizes the runs of one phase (parse or solve) of a day part, each
+// run being timed on its own
+type benchResult struct {
+	Day         int     `json:"day"`
+	Part        int     `json:"part"`
+	Phase       string  `json:"phase"`
+	Runs        int     `json:"runs"`
+	MinNs       float64 `json:"minNs"`
+	MedianNs    float64 `json:"medianNs"`
+	P95Ns       float64 `json:"p95Ns"`
+	BytesPerOp  float64 `json:"bytesPerOp"`
+	AllocsPerOp float64 `json:"allocsPerOp"`
+}
+
+func (r benchResult) key() string {
+	return fmt.Sprintf("day %d part %d %s", r.Day, r.Part, r.Phase)
+}
+
+func parseBenchOutput(output string) []benchSample {
+	var samples []benchSample
+	for _, line := range strings.Split(output, "\n") {
+		match := benchLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		part, _ := strconv.Atoi(match[1])
+		sample := benchSample{part: part, phase: match[2]}
+		sample.ns, _ = strconv.ParseFloat(match[3], 64)
+		if match[4] != "" {
+			sample.bytes, _ = strconv.ParseFloat(match[4], 64)
+			sample.allocs, _ = strconv.ParseFloat(match[5], 64)
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// percentile returns the nearest-rank percentile p (0 to 1) of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return percentile(sorted, 0.5)
+}
+
+// summarizeSamples groups the samples of a day by part and phase
+func summarizeSamples(day int, samples []benchSample) []benchResult {
+
+	type group struct {
+		part  int
+		phase string
+	}
+	grouped := make(map[group][]benchSample)
+	var order []group
+	for _, sample := range samples {
+		g := group{sample.part, sample.phase}
+		if _, ok := grouped[g]; !ok {
+			order = append(order, g)
+		}
+		grouped[g] = append(grouped[g], sample)
+	}
+
+	var results []benchResult
+	for _, g := range order {
+		var ns, bytes, allocs []float64
+		for _, sample := range grouped[g] {
+			ns = append(ns, sample.ns)
+			bytes = append(bytes, sample.bytes)
+			allocs = append(allocs, sample.allocs)
+		}
+		sort.Float64s(ns)
+		results = append(results, benchResult{
+			Day:         day,
+			Part:        g.part,
+			Phase:       g.phase,
+			Runs:        len(ns),
+			MinNs:       ns[0],
+			MedianNs:    percentile(ns, 0.5),
+			P95Ns:       percentile(ns, 0.95),
+			BytesPerOp:  median(bytes),
+			AllocsPerOp: median(allocs),
+		})
+	}
+	return results
+}
+
+// benchArgs returns the go test arguments running each part benchmark of a day runs
+// times. With a single iteration per benchmark, go test reports the wall time and
+// allocations of each run rather than an average over many iterations, so that the
+// min, median and p95 are taken over the runs themselves.
+func benchArgs(runs int) []string {
+	return []string{"test", "-run", "^$", "-bench", "^BenchmarkPart", "-benchmem", "-benchtime", "1x", "-count", strconv.Itoa(runs), "."}
+}
+
+// runBenchmarks runs the part benchmarks of a day runs times with go test
+func runBenchmarks(root string, s solver, runs int) ([]benchResult, error) {
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", benchArgs(runs)...)
+	cmd.Dir = filepath.Join(root, s.dir)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.New("fail to benchmark day " + strconv.Itoa(s.day) + " due to error " + err.Error() + ": " + strings.TrimSpace(stdout.String()+stderr.String()))
+	}
+
+	return summarizeSamples(s.day, parseBenchOutput(stdout.String())), nil
+}
+
+// compareBenchmarks returns the results whose median time or allocations per run
+// grew by more than threshold percent over the baseline
+func compareBenchmarks(results []benchResult, baseline []benchResult, threshold float64) []string {
+
+	baselineByKey := make(map[string]benchResult)
+	for _, b := range baseline {
+		baselineByKey[b.key()] = b
+	}
+
+	var regressions []string
+	for _, r := range results {
+		b, ok := baselineByKey[r.key()]
+		if !ok {
+			continue
+		}
+		if b.MedianNs > 0 && r.MedianNs > b.MedianNs*(1+threshold/100) {
+			regressions = append(regressions, fmt.Sprintf("%s: median %s, baseline %s (+%.1f%%)", r.key(), formatNs(r.MedianNs), formatNs(b.MedianNs), (r.MedianNs/b.MedianNs-1)*100))
+		}
+		if r.AllocsPerOp > b.AllocsPerOp*(1+threshold/100) {
+			regressions = append(regressions, fmt.Sprintf("%s: %.0f allocs per run, baseline %.0f", r.key(), r.AllocsPerOp, b.AllocsPerOp))
+		}
+	}
+	return regressions
+}
+
+func formatNs(ns float64) string {
+	switch {
+	case ns >= 1e9:
+		return fmt.Sprintf("%.2fs", ns/1e9)
+	case ns >= 1e6:
+		return fmt.Sprintf("%.2fms", ns/1e6)
+	case ns >= 1e3:
+		return fmt.Sprintf("%.2fµs", ns/1e3)
+	default:
+		return fmt.Sprintf("%.0fns", ns)
+	}
+}
+
+func printBenchTable(w io.Writer, results []benchResult) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "day\tpart\tphase\truns\tmin\tmedian\tp95\tB/run\tallocs/run\t")
+	for _, r := range results {
+		fmt.Fprintf(table, "%d\t%d\t%s\t%d\t%s\t%s\t%s\t%.0f\t%.0f\t\n", r.Day, r.Part, r.Phase, r.Runs, formatNs(r.MinNs), formatNs(r.MedianNs), formatNs(r.P95Ns), r.BytesPerOp, r.AllocsPerOp)
+	}
+	table.Flush()
+}
+
+func readBenchResults(path string) ([]benchResult, error) {
+	var results []benchResult
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.New("fail to read benchmarks from " + path + " due to error " + err.Error())
+	}
+	if err := json.Unmarshal(content, &results); err != nil {
+		return nil, errors.New("fail to parse benchmarks from " + path + " due to error " + err.Error())
+	}
+	return results, nil
+}
+
+func runBench(args []string) error {
+
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	day := flags.Int("day", 0, "only benchmark this day, every day when 0")
+	runs := flags.Int("runs", 20, "number of runs of each benchmark, each of them timed on its own")
+	asJSON := flags.Bool("json", false, "print the results as JSON instead of a table")
+	save := flags.String("save", "", "save the results as JSON to this file, to be used as a baseline")
+	baselinePath := flags.String("baseline", "", "compare the results with a saved baseline")
+	threshold := flags.Float64("threshold", 10, "median time or allocations increase, in percent, reported as a regression")
+	root := flags.String("root", defaultRepoRoot(), "repository directory holding the day solvers")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *runs < 1 {
+		return fmt.Errorf("invalid runs %d, expected at least 1", *runs)
+	}
+
+	var results []benchResult
+	for _, s := range solvers {
+		if *day != 0 && s.day != *day {
+			continue
+		}
+		dayResults, err := runBenchmarks(*root, s, *runs)
+		if err != nil {
+			return err
+		}
+		results = append(results, dayResults...)
+	}
+
+	content, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		fmt.Println(string(content))
+	} else {
+		printBenchTable(os.Stdout, results)
+	}
+
+	if *save != "" {
+		if err := writeFileAtomic(*save, append(content, '\n')); err != nil {
+			return errors.New("fail to save benchmarks to " + *save + " due to error " + err.Error())
+		}
+	}
+
+	if *baselinePath != "" {
+		baseline, err := readBenchResults(*baselinePath)
+		if err != nil {
+			return err
+		}
+		regressions := compareBenchmarks(results, baseline, *threshold)
+		for _, regression := range regressions {
+			fmt.Fprintln(os.Stderr, "regression:", regression)
+		}
+		if len(regressions) > 0 {
+			return fmt.Errorf("%d regressions against %s", len(regressions), *baselinePath)
+		}
+	}
+	return nil
+}
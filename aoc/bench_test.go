@@ -0,0 +1,90 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const benchOutput = `goos: linux
+goarch: amd64
+pkg: day3
+BenchmarkPart1/parse-8         	    2667	    400 ns/op	  316721 B/op	    6984 allocs/op
+BenchmarkPart1/parse-8         	    2641	    100 ns/op	  316723 B/op	    6984 allocs/op
+BenchmarkPart1/parse-8         	    2641	    300 ns/op	  316723 B/op	    6984 allocs/op
+BenchmarkPart1/solve-8         	    3028	    502.5 ns/op	   53848 B/op	    1220 allocs/op
+BenchmarkPart2/solve           	     655	    2051322 ns/op
+PASS
+ok  	day3	12.345s
+`
+
+func TestParseBenchOutput(t *testing.T) {
+	samples := parseBenchOutput(benchOutput)
+
+	expected := []benchSample{
+		{part: 1, phase: "parse", ns: 400, bytes: 316721, allocs: 6984},
+		{part: 1, phase: "parse", ns: 100, bytes: 316723, allocs: 6984},
+		{part: 1, phase: "parse", ns: 300, bytes: 316723, allocs: 6984},
+		{part: 1, phase: "solve", ns: 502.5, bytes: 53848, allocs: 1220},
+		{part: 2, phase: "solve", ns: 2051322},
+	}
+	if !reflect.DeepEqual(samples, expected) {
+		t.Errorf("Expected %v, got %v", expected, samples)
+	}
+}
+
+func TestSummarizeSamples(t *testing.T) {
+	results := summarizeSamples(3, parseBenchOutput(benchOutput))
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %v", results)
+	}
+	expected := benchResult{Day: 3, Part: 1, Phase: "parse", Runs: 3, MinNs: 100, MedianNs: 300, P95Ns: 400, BytesPerOp: 316723, AllocsPerOp: 6984}
+	if results[0] != expected {
+		t.Errorf("Expected %v, got %v", expected, results[0])
+	}
+}
+
+func TestBenchArgs(t *testing.T) {
+	args := strings.Join(benchArgs(20), " ")
+	// one iteration per sample, so that each sample is a run
+	if !strings.Contains(args, "-benchtime 1x") || !strings.Contains(args, "-count 20") {
+		t.Errorf("Expected 20 runs of a single iteration, got %q", args)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+	testCases := []struct {
+		p        float64
+		expected float64
+	}{
+		{0, 1},
+		{0.5, 10},
+		{0.95, 19},
+		{1, 20},
+	}
+
+	for _, testCase := range testCases {
+		if result := percentile(sorted, testCase.p); result != testCase.expected {
+			t.Errorf("For percentile %v, expected %v, but got %v", testCase.p, testCase.expected, result)
+		}
+	}
+}
+
+func TestCompareBenchmarks(t *testing.T) {
+	baseline := []benchResult{
+		{Day: 1, Part: 2, Phase: "solve", MedianNs: 1000, AllocsPerOp: 10},
+		{Day: 3, Part: 1, Phase: "parse", MedianNs: 1000, AllocsPerOp: 10},
+	}
+	results := []benchResult{
+		{Day: 1, Part: 2, Phase: "solve", MedianNs: 1050, AllocsPerOp: 10},
+		{Day: 3, Part: 1, Phase: "parse", MedianNs: 1200, AllocsPerOp: 20},
+		{Day: 4, Part: 1, Phase: "parse", MedianNs: 99999, AllocsPerOp: 99},
+	}
+
+	regressions := compareBenchmarks(results, baseline, 10)
+	if len(regressions) != 2 {
+		t.Errorf("Expected a time and an allocation regression for day 3, got %v", regressions)
+	}
+}
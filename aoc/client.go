@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minimal delay between two requests sent to the website, whatever the command
+const defaultRequestInterval = 5 * time.Second
+
+const userAgent = "github.com/gaellm/adventofcode2023 aoc tool"
+
+type client struct {
+	baseURL  string
+	session  string
+	http     *http.Client
+	interval time.Duration
+	// file keeping the time of the last request, shared by every aoc invocation
+	stampFile string
+}
+
+func newClient(conf config) (*client, error) {
+	if conf.session == "" {
+		return nil, errors.New("no session token found, set AOC_SESSION or write it to the aoc/session file of the user config directory")
+	}
+
+	return &client{
+		baseURL:   conf.baseURL,
+		session:   conf.session,
+		http:      &http.Client{Timeout: 30 * time.Second},
+		interval:  defaultRequestInterval,
+		stampFile: filepath.Join(conf.cacheDir, "last-request"),
+	}, nil
+}
+
+// throttle waits until interval has elapsed since the last request recorded in
+// the stamp file, then records the current request
+func (c *client) throttle() error {
+
+	content, err := os.ReadFile(c.stampFile)
+	if err == nil {
+		last, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(content)))
+		if err == nil {
+			if wait := c.interval - time.Since(last); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.stampFile), 0o755); err != nil {
+		return errors.New("fail to create the cache directory due to error " + err.Error())
+	}
+	return os.WriteFile(c.stampFile, []byte(time.Now().Format(time.RFC3339Nano)), 0o644)
+}
+
+// do sends an authenticated request to the website and returns the response body
+// when the status is 200 OK
+func (c *client) do(method string, path string, body io.Reader, contentType string) ([]byte, error) {
+
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.session})
+	req.Header.Set("User-Agent", userAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if err := c.throttle(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.New("fail to read response of " + path + " due to error " + err.Error())
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return content, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retry := resp.Header.Get("Retry-After")
+		if seconds, err := strconv.Atoi(retry); err == nil {
+			return nil, fmt.Errorf("rate limited by %s, retry in %d seconds", c.baseURL, seconds)
+		}
+		return nil, fmt.Errorf("rate limited by %s", c.baseURL)
+	default:
+		return nil, fmt.Errorf("%s %s answered %s: %s", method, path, resp.Status, strings.TrimSpace(string(content)))
+	}
+}
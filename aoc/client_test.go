@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientRequiresSession(t *testing.T) {
+	_, err := newClient(config{baseURL: "http://localhost"})
+	if err == nil {
+		t.Error("Expected an error without session token")
+	}
+}
+
+func TestClientThrottle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	c := newTestClient(t, server, cacheDir)
+	c.interval = 200 * time.Millisecond
+
+	if _, err := c.do("GET", "/", nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new client shares the last request time through the cache directory
+	other := newTestClient(t, server, cacheDir)
+	other.interval = 200 * time.Millisecond
+
+	start := time.Now()
+	if _, err := other.do("GET", "/", nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("Expected the second request to wait for the interval, it took %v", elapsed)
+	}
+}
+
+func TestClientRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "42")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server, t.TempDir())
+	_, err := c.do("GET", "/", nil, "")
+	if err == nil || !strings.Contains(err.Error(), "42 seconds") {
+		t.Errorf("Expected a rate limit error with the retry delay, got %v", err)
+	}
+}
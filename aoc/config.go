@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultYear    = 2023
+	defaultBaseURL = "https://adventofcode.com"
+)
+
+type config struct {
+	session  string
+	baseURL  string
+	cacheDir string
+}
+
+// loadConfig builds the configuration from the environment, falling back to the
+// files stored in the user config directory:
+//   - AOC_SESSION or <config dir>/aoc/session holds the session cookie value
+//   - AOC_BASE_URL overrides the website address
+//   - AOC_CACHE_DIR overrides the <cache dir>/aoc cache root
+func loadConfig() (config, error) {
+	conf := config{
+		session:  strings.TrimSpace(os.Getenv("AOC_SESSION")),
+		baseURL:  strings.TrimRight(os.Getenv("AOC_BASE_URL"), "/"),
+		cacheDir: os.Getenv("AOC_CACHE_DIR"),
+	}
+
+	if conf.baseURL == "" {
+		conf.baseURL = defaultBaseURL
+	}
+
+	if conf.session == "" {
+		configDir, err := os.UserConfigDir()
+		if err == nil {
+			content, err := os.ReadFile(filepath.Join(configDir, "aoc", "session"))
+			if err == nil {
+				conf.session = strings.TrimSpace(string(content))
+			}
+		}
+	}
+
+	if conf.cacheDir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return conf, errors.New("fail to locate the user cache directory due to error " + err.Error())
+		}
+		conf.cacheDir = filepath.Join(cacheDir, "aoc")
+	}
+
+	return conf, nil
+}
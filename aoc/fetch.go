@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// cachedInputPath returns where the input of a day is kept, one directory per year
+// and per day
+func cachedInputPath(cacheDir string, year int, day int) string {
+	return filepath.Join(cacheDir, strconv.Itoa(year), fmt.Sprintf("day%02d", day), "input.txt")
+}
+
+// writeFileAtomic writes content to a temporary file renamed to path, so an
+// interrupted download never leaves a truncated file behind
+func writeFileAtomic(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// fetchInput returns the path of the cached input of a day, downloading it only
+// when it is not already in the cache
+func fetchInput(c *client, cacheDir string, year int, day int) (string, bool, error) {
+
+	path := cachedInputPath(cacheDir, year, day)
+	if _, err := os.Stat(path); err == nil {
+		return path, true, nil
+	}
+
+	content, err := c.do("GET", fmt.Sprintf("/%d/day/%d/input", year, day), nil, "")
+	if err != nil {
+		return "", false, errors.New("fail to download input of day " + strconv.Itoa(day) + " due to error " + err.Error())
+	}
+
+	if err := writeFileAtomic(path, content); err != nil {
+		return "", false, errors.New("fail to cache input to " + path + " due to error " + err.Error())
+	}
+	return path, false, nil
+}
+
+func validateDay(year int, day int) error {
+	if day < 1 || day > 25 {
+		return fmt.Errorf("invalid day %d, expected 1 to 25", day)
+	}
+	if year < 2015 {
+		return fmt.Errorf("invalid year %d, the first event is 2015", year)
+	}
+	return nil
+}
+
+func runFetch(args []string) error {
+
+	flags := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	day := flags.Int("day", 0, "day of the puzzle (1-25)")
+	year := flags.Int("year", defaultYear, "year of the event")
+	out := flags.String("out", "", "also copy the input to this file, e.g. ../day5/input.txt")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := validateDay(*year, *day); err != nil {
+		return err
+	}
+
+	conf, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	path := cachedInputPath(conf.cacheDir, *year, *day)
+	cached := true
+	if _, err := os.Stat(path); err != nil {
+		c, err := newClient(conf)
+		if err != nil {
+			return err
+		}
+		path, cached, err = fetchInput(c, conf.cacheDir, *year, *day)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *out != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(*out, content); err != nil {
+			return errors.New("fail to copy input to " + *out + " due to error " + err.Error())
+		}
+		path = *out
+	}
+
+	if cached {
+		fmt.Println("input already cached:", path)
+	} else {
+		fmt.Println("input downloaded:", path)
+	}
+	return nil
+}
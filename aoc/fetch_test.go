@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// newTestClient returns a client talking to server without any request delay
+func newTestClient(t *testing.T, server *httptest.Server, cacheDir string) *client {
+	c, err := newClient(config{session: "secret", baseURL: server.URL, cacheDir: cacheDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.interval = 0
+	return c
+}
+
+func TestCachedInputPath(t *testing.T) {
+	expected := filepath.Join("cache", "2023", "day04", "input.txt")
+	if path := cachedInputPath("cache", 2023, 4); path != expected {
+		t.Errorf("Expected %s, got %s", expected, path)
+	}
+}
+
+func TestFetchInput(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "secret" {
+			http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != "/2023/day/4/input" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("Card 1: 1 2 | 2 3\n"))
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	c := newTestClient(t, server, cacheDir)
+
+	path, cached, err := fetchInput(c, cacheDir, 2023, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached {
+		t.Error("Expected a download on first fetch")
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "Card 1: 1 2 | 2 3\n" {
+		t.Errorf("Unexpected cached content %q", content)
+	}
+
+	// Second fetch must be served from the cache
+	_, cached, err = fetchInput(c, cacheDir, 2023, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cached || atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("Expected the input to be cached, server was called %d times", calls)
+	}
+
+	// Errors are reported and nothing is cached
+	_, _, err = fetchInput(c, cacheDir, 2023, 5)
+	if err == nil {
+		t.Error("Expected an error for a missing day")
+	}
+	if _, err := os.Stat(cachedInputPath(cacheDir, 2023, 5)); err == nil {
+		t.Error("Expected no cached input after a failed download")
+	}
+
+	c.session = "wrong"
+	_, _, err = fetchInput(c, cacheDir, 2023, 6)
+	if err == nil {
+		t.Error("Expected an error for a wrong session")
+	}
+}
+
+func TestValidateDay(t *testing.T) {
+	testCases := []struct {
+		year        int
+		day         int
+		expectedErr bool
+	}{
+		{2023, 1, false},
+		{2023, 25, false},
+		{2023, 0, true},
+		{2023, 26, true},
+		{2014, 1, true},
+	}
+
+	for _, testCase := range testCases {
+		err := validateDay(testCase.year, testCase.day)
+		if (err != nil) != testCase.expectedErr {
+			t.Errorf("For year %d day %d, expected error %v, but got %v", testCase.year, testCase.day, testCase.expectedErr, err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: aoc <command> [flags]
+
+commands:
+  fetch    download a day puzzle input into the local cache
+  submit   post a day answer, keeping a history of the attempts
+  verify   rerun the solvers and compare with the recorded answers
+  bench    benchmark the solvers, optionally against a saved baseline
+`
+
+func main() {
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "fetch":
+		err = runFetch(os.Args[2:])
+	case "submit":
+		err = runSubmit(os.Args[2:])
+	case "verify":
+		err = runVerify(os.Args[2:])
+	case "bench":
+		err = runBench(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "aoc "+os.Args[1]+": "+err.Error())
+		os.Exit(1)
+	}
+}
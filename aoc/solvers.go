@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type solver struct {
+	day int
+	dir string
+	// parts maps a part number to the expression capturing its answer in the
+	// solver output
+	parts map[int]*regexp.Regexp
+}
+
+var solvers = []solver{
+	{
+		day: 1,
+		dir: "day1",
+		parts: map[int]*regexp.Regexp{
+			1: regexp.MustCompile(`^Part 1 - sum of calibration values:\s+(\d+)$`),
+			2: regexp.MustCompile(`^Part 2 - sum of calibration values with spelled out digits:\s+(\d+)$`),
+		},
+	},
+	{
+		day: 2,
+		dir: "day2",
+		parts: map[int]*regexp.Regexp{
+			1: regexp.MustCompile(`^possible IDs sum:\s+(\d+)$`),
+			2: regexp.MustCompile(`^power sets sum:\s+(\d+)$`),
+		},
+	},
+	{
+		day: 3,
+		dir: "day3",
+		parts: map[int]*regexp.Regexp{
+			1: regexp.MustCompile(`^Part 1 - sum of part numbers:\s+(\d+)$`),
+			2: regexp.MustCompile(`^Part 2 - sum of gears ratio:\s+(\d+)$`),
+		},
+	},
+	{
+		day: 4,
+		dir: "day4",
+		parts: map[int]*regexp.Regexp{
+			1: regexp.MustCompile(`^Part1 - Sum of cards points:\s+(\d+)$`),
+			2: regexp.MustCompile(`^Part2 - Sum of cards:\s+(\d+)$`),
+		},
+	},
+}
+
+func findSolver(day int) (solver, error) {
+	for _, s := range solvers {
+		if s.day == day {
+			return s, nil
+		}
+	}
+	return solver{}, fmt.Errorf("no solver registered for day %d", day)
+}
+
+// sortedParts returns the parts a solver answers, in order
+func (s solver) sortedParts() []int {
+	var parts []int
+	for part := range s.parts {
+		parts = append(parts, part)
+	}
+	sort.Ints(parts)
+	return parts
+}
+
+// defaultRepoRoot returns the repository directory holding the day solvers, which
+// is the parent of this source file directory
+func defaultRepoRoot() string {
+	_, source, _, ok := runtime.Caller(0)
+	if ok {
+		root := filepath.Dir(filepath.Dir(source))
+		if _, err := os.Stat(root); err == nil {
+			return root
+		}
+	}
+	return ".."
+}
+
+// parseSolverOutput extracts the answers printed by a solver, by part
+func parseSolverOutput(s solver, output string) map[int]string {
+	answers := make(map[int]string)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		for part, re := range s.parts {
+			if match := re.FindStringSubmatch(line); match != nil {
+				answers[part] = match[1]
+			}
+		}
+	}
+	return answers
+}
+
+// runSolver runs a day solver with go run and returns the answers it printed, by
+// part. An empty inputPath lets the solver use its own default input. When the
+// solver fails or misses a part, the answers printed anyway are returned with the
+// error.
+func runSolver(root string, s solver, inputPath string) (map[int]string, error) {
+
+	args := []string{"run", "."}
+	if inputPath != "" {
+		absPath, err := filepath.Abs(inputPath)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--input", absPath)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", args...)
+	cmd.Dir = filepath.Join(root, s.dir)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	answers := parseSolverOutput(s, stdout.String())
+	if err != nil {
+		return answers, errors.New("fail to run solver of day " + strconv.Itoa(s.day) + " due to error " + err.Error() + ": " + strings.TrimSpace(stderr.String()))
+	}
+	for _, part := range s.sortedParts() {
+		if _, ok := answers[part]; !ok {
+			return answers, fmt.Errorf("solver of day %d printed no answer for part %d", s.day, part)
+		}
+	}
+	return answers, nil
+}
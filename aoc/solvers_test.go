@@ -0,0 +1,43 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseSolverOutput(t *testing.T) {
+	s, err := findSolver(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := "debug line 12\nPart 1 - sum of part numbers:  4361\nPart 2 - sum of gears ratio:  467835\n"
+	expected := map[int]string{1: "4361", 2: "467835"}
+	if answers := parseSolverOutput(s, output); !reflect.DeepEqual(answers, expected) {
+		t.Errorf("Expected %v, got %v", expected, answers)
+	}
+}
+
+func TestFindSolver(t *testing.T) {
+	if _, err := findSolver(25); err == nil {
+		t.Error("Expected an error for a day without solver")
+	}
+}
+
+func TestRunSolver(t *testing.T) {
+	root := defaultRepoRoot()
+	s, err := findSolver(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers, err := runSolver(root, s, filepath.Join(root, "day2", "input_test.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[int]string{1: "8", 2: "2286"}
+	if !reflect.DeepEqual(answers, expected) {
+		t.Errorf("Expected %v, got %v", expected, answers)
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type verdict string
+
+const (
+	verdictCorrect       verdict = "correct"
+	verdictTooHigh       verdict = "too high"
+	verdictTooLow        verdict = "too low"
+	verdictWrong         verdict = "wrong"
+	verdictWait          verdict = "wait"
+	verdictAlreadySolved verdict = "already solved"
+	verdictUnknown       verdict = "unknown"
+)
+
+// attempt is one submission, as stored in the history file
+type attempt struct {
+	Time        time.Time `json:"time"`
+	Year        int       `json:"year"`
+	Day         int       `json:"day"`
+	Part        int       `json:"part"`
+	Answer      string    `json:"answer"`
+	Verdict     verdict   `json:"verdict"`
+	WaitSeconds int       `json:"waitSeconds,omitempty"`
+	Message     string    `json:"message,omitempty"`
+}
+
+var (
+	articlePattern  = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagPattern      = regexp.MustCompile(`<[^>]*>`)
+	leftWaitPattern = regexp.MustCompile(`You have (?:(\d+)m )?(\d+)s left to wait`)
+	waitPattern     = regexp.MustCompile(`[Pp]lease wait (one|\d+) minutes?`)
+)
+
+// historyPath returns the file keeping the submissions of a day, next to its cached
+// input
+func historyPath(cacheDir string, year int, day int) string {
+	return filepath.Join(filepath.Dir(cachedInputPath(cacheDir, year, day)), "submissions.jsonl")
+}
+
+// readHistory returns the attempts stored in a history file, one JSON object per
+// line. A missing file is an empty history.
+func readHistory(path string) ([]attempt, error) {
+	var history []attempt
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, errors.New("fail to read history from " + path + " due to error " + err.Error())
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNb := 1; scanner.Scan(); lineNb++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var a attempt
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil {
+			return nil, fmt.Errorf("fail to parse line %d of %s: %v", lineNb, path, err)
+		}
+		history = append(history, a)
+	}
+	return history, scanner.Err()
+}
+
+func appendHistory(path string, a attempt) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	line, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// parseVerdict reads the page answered to a submission and returns its verdict,
+// the delay before the next submission is allowed, and the page message
+func parseVerdict(page string) (verdict, int, string) {
+
+	message := page
+	if match := articlePattern.FindStringSubmatch(page); match != nil {
+		message = match[1]
+	}
+	message = strings.Join(strings.Fields(tagPattern.ReplaceAllString(message, "")), " ")
+
+	wait := 0
+	if match := leftWaitPattern.FindStringSubmatch(message); match != nil {
+		minutes, _ := strconv.Atoi(match[1])
+		seconds, _ := strconv.Atoi(match[2])
+		wait = minutes*60 + seconds
+	} else if match := waitPattern.FindStringSubmatch(message); match != nil {
+		minutes := 1
+		if match[1] != "one" {
+			minutes, _ = strconv.Atoi(match[1])
+		}
+		wait = minutes * 60
+	}
+
+	switch {
+	case strings.Contains(message, "That's the right answer"):
+		return verdictCorrect, 0, message
+	case strings.Contains(message, "answer too recently"):
+		return verdictWait, wait, message
+	case strings.Contains(message, "your answer is too high"):
+		return verdictTooHigh, wait, message
+	case strings.Contains(message, "your answer is too low"):
+		return verdictTooLow, wait, message
+	case strings.Contains(message, "That's not the right answer"):
+		return verdictWrong, wait, message
+	case strings.Contains(message, "Did you already complete it"):
+		return verdictAlreadySolved, 0, message
+	default:
+		return verdictUnknown, wait, message
+	}
+}
+
+// compareAnswers compares two integer answers, reporting false when one of them is
+// not an integer
+func compareAnswers(a string, b string) (int, bool) {
+	x, ok := new(big.Int).SetString(a, 10)
+	if !ok {
+		return 0, false
+	}
+	y, ok := new(big.Int).SetString(b, 10)
+	if !ok {
+		return 0, false
+	}
+	return x.Cmp(y), true
+}
+
+// checkAnswer refuses an answer that the history already knows to be wrong, that
+// falls outside the bounds given by previous too high or too low answers, or that
+// would be sent before the end of a waiting delay
+func checkAnswer(history []attempt, part int, answer string, now time.Time) error {
+
+	for _, a := range history {
+		if a.Part != part {
+			continue
+		}
+
+		if a.WaitSeconds > 0 {
+			if until := a.Time.Add(time.Duration(a.WaitSeconds) * time.Second); now.Before(until) {
+				return fmt.Errorf("submissions are blocked for %v more", until.Sub(now).Round(time.Second))
+			}
+		}
+
+		switch a.Verdict {
+		case verdictCorrect:
+			if a.Answer == answer {
+				return fmt.Errorf("answer %s was already accepted", answer)
+			}
+			return fmt.Errorf("part %d is already solved with answer %s", part, a.Answer)
+		case verdictAlreadySolved:
+			return fmt.Errorf("part %d is already solved", part)
+		case verdictWrong, verdictTooHigh, verdictTooLow:
+			if a.Answer == answer {
+				return fmt.Errorf("answer %s is already known to be wrong (%s)", answer, a.Verdict)
+			}
+		}
+
+		if a.Verdict == verdictTooHigh {
+			if cmp, ok := compareAnswers(answer, a.Answer); ok && cmp >= 0 {
+				return fmt.Errorf("answer %s is not below %s, which is already too high", answer, a.Answer)
+			}
+		}
+		if a.Verdict == verdictTooLow {
+			if cmp, ok := compareAnswers(answer, a.Answer); ok && cmp <= 0 {
+				return fmt.Errorf("answer %s is not above %s, which is already too low", answer, a.Answer)
+			}
+		}
+	}
+
+	return nil
+}
+
+// submitAnswer posts an answer and returns the attempt built from the response
+func submitAnswer(c *client, year int, day int, part int, answer string) (attempt, error) {
+
+	form := url.Values{}
+	form.Set("level", strconv.Itoa(part))
+	form.Set("answer", answer)
+
+	page, err := c.do("POST", fmt.Sprintf("/%d/day/%d/answer", year, day), strings.NewReader(form.Encode()), "application/x-www-form-urlencoded")
+	if err != nil {
+		return attempt{}, errors.New("fail to submit answer due to error " + err.Error())
+	}
+
+	v, wait, message := parseVerdict(string(page))
+	return attempt{
+		Time:        time.Now(),
+		Year:        year,
+		Day:         day,
+		Part:        part,
+		Answer:      answer,
+		Verdict:     v,
+		WaitSeconds: wait,
+		Message:     message,
+	}, nil
+}
+
+func runSubmit(args []string) error {
+
+	flags := flag.NewFlagSet("submit", flag.ContinueOnError)
+	day := flags.Int("day", 0, "day of the puzzle (1-25)")
+	part := flags.Int("part", 0, "part of the puzzle (1 or 2)")
+	year := flags.Int("year", defaultYear, "year of the event")
+	answer := flags.String("answer", "", "answer to submit, computed by running the day solver when empty")
+	input := flags.String("input", "", "input given to the solver, its own input.txt when empty")
+	root := flags.String("root", defaultRepoRoot(), "repository directory holding the day solvers")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := validateDay(*year, *day); err != nil {
+		return err
+	}
+	if *part != 1 && *part != 2 {
+		return fmt.Errorf("invalid part %d, expected 1 or 2", *part)
+	}
+
+	conf, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	c, err := newClient(conf)
+	if err != nil {
+		return err
+	}
+
+	if *answer == "" {
+		s, err := findSolver(*day)
+		if err != nil {
+			return err
+		}
+		answers, err := runSolver(*root, s, *input)
+		computed, ok := answers[*part]
+		if !ok {
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("solver of day %d does not answer part %d", *day, *part)
+		}
+		*answer = computed
+	}
+	*answer = strings.TrimSpace(*answer)
+
+	path := historyPath(conf.cacheDir, *year, *day)
+	history, err := readHistory(path)
+	if err != nil {
+		return err
+	}
+	if err := checkAnswer(history, *part, *answer, time.Now()); err != nil {
+		return errors.New("refusing to submit: " + err.Error())
+	}
+
+	a, err := submitAnswer(c, *year, *day, *part, *answer)
+	if err != nil {
+		return err
+	}
+	if err := appendHistory(path, a); err != nil {
+		return errors.New("fail to record attempt to " + path + " due to error " + err.Error())
+	}
+
+	fmt.Println(a.Message)
+	if a.Verdict != verdictCorrect {
+		return fmt.Errorf("answer %s for day %d part %d: %s", a.Answer, *day, *part, a.Verdict)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	pageCorrect  = `<main><article><p>That's the right answer!  You are <em>one gold star</em> closer to restoring snow operations.</p></article></main>`
+	pageTooHigh  = `<main><article><p>That's not the right answer; your answer is too high.  If you're stuck, make sure you're using the full input data. Please wait one minute before trying again. [<a href="/2023/day/4">Return to Day 4</a>]</p></article></main>`
+	pageTooLow   = `<main><article><p>That's not the right answer; your answer is too low.  Please wait 5 minutes before trying again.</p></article></main>`
+	pageWrong    = `<main><article><p>That's not the right answer.  If you're stuck, make sure you're using the full input data.</p></article></main>`
+	pageTooSoon  = `<main><article><p>You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 1m 12s left to wait.</p></article></main>`
+	pageComplete = `<main><article><p>You don't seem to be solving the right level.  Did you already complete it?</p></article></main>`
+)
+
+func TestParseVerdict(t *testing.T) {
+	testCases := []struct {
+		page            string
+		expectedVerdict verdict
+		expectedWait    int
+	}{
+		{pageCorrect, verdictCorrect, 0},
+		{pageTooHigh, verdictTooHigh, 60},
+		{pageTooLow, verdictTooLow, 300},
+		{pageWrong, verdictWrong, 0},
+		{pageTooSoon, verdictWait, 72},
+		{pageComplete, verdictAlreadySolved, 0},
+		{"<html>maintenance</html>", verdictUnknown, 0},
+	}
+
+	for _, testCase := range testCases {
+		v, wait, message := parseVerdict(testCase.page)
+		if v != testCase.expectedVerdict || wait != testCase.expectedWait {
+			t.Errorf("For page %q, expected %s and wait %d, but got %s and wait %d (%s)", testCase.page, testCase.expectedVerdict, testCase.expectedWait, v, wait, message)
+		}
+	}
+}
+
+func TestCheckAnswer(t *testing.T) {
+	now := time.Date(2023, 12, 4, 10, 0, 0, 0, time.UTC)
+	history := []attempt{
+		{Time: now.Add(-time.Hour), Part: 1, Answer: "500", Verdict: verdictTooHigh, WaitSeconds: 60},
+		{Time: now.Add(-time.Hour), Part: 1, Answer: "100", Verdict: verdictTooLow, WaitSeconds: 60},
+		{Time: now.Add(-time.Hour), Part: 1, Answer: "250", Verdict: verdictWrong},
+		{Time: now.Add(-30 * time.Second), Part: 2, Answer: "7", Verdict: verdictTooLow, WaitSeconds: 60},
+	}
+
+	testCases := []struct {
+		part        int
+		answer      string
+		expectedErr bool
+	}{
+		{1, "300", false},
+		{1, "250", true},
+		{1, "500", true},
+		{1, "600", true},
+		{1, "100", true},
+		{1, "99", true},
+		{1, "abc", false},
+		{2, "8", true},
+	}
+
+	for _, testCase := range testCases {
+		err := checkAnswer(history, testCase.part, testCase.answer, now)
+		if (err != nil) != testCase.expectedErr {
+			t.Errorf("For part %d answer %s, expected error %v, but got %v", testCase.part, testCase.answer, testCase.expectedErr, err)
+		}
+	}
+
+	// Once the delay is over, part 2 can be submitted again
+	if err := checkAnswer(history, 2, "8", now.Add(time.Minute)); err != nil {
+		t.Errorf("Expected no error after the waiting delay, got %v", err)
+	}
+
+	// A solved part refuses any other answer
+	solved := append(history, attempt{Time: now, Part: 1, Answer: "300", Verdict: verdictCorrect})
+	if err := checkAnswer(solved, 1, "301", now); err == nil {
+		t.Error("Expected an error for an already solved part")
+	}
+}
+
+func TestSubmitAnswer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/2023/day/4/answer" || r.FormValue("level") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.FormValue("answer") == "13" {
+			w.Write([]byte(pageCorrect))
+			return
+		}
+		w.Write([]byte(pageTooHigh))
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	c := newTestClient(t, server, cacheDir)
+	path := historyPath(cacheDir, 2023, 4)
+
+	for _, answer := range []string{"20", "13"} {
+		a, err := submitAnswer(c, 2023, 4, 1, answer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := appendHistory(path, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := readHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var verdicts []verdict
+	for _, a := range history {
+		verdicts = append(verdicts, a.Verdict)
+	}
+	if !reflect.DeepEqual(verdicts, []verdict{verdictTooHigh, verdictCorrect}) {
+		t.Errorf("Expected too high then correct, got %v", verdicts)
+	}
+
+	if _, err := submitAnswer(c, 2023, 5, 1, "13"); err == nil {
+		t.Error("Expected an error for a failed request")
+	}
+}
+
+func TestReadHistoryMissingFile(t *testing.T) {
+	history, err := readHistory(filepath.Join(t.TempDir(), "none.jsonl"))
+	if err != nil || len(history) != 0 {
+		t.Errorf("Expected an empty history, got %v and %v", history, err)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// version of the answers file format
+const answersVersion = 1
+
+// recordedAnswer is an accepted answer of a day part for a given input
+type recordedAnswer struct {
+	Day       int    `json:"day"`
+	Part      int    `json:"part"`
+	Input     string `json:"input"`
+	InputHash string `json:"inputSha256"`
+	Answer    string `json:"answer"`
+}
+
+type answersFile struct {
+	Version int              `json:"version"`
+	Answers []recordedAnswer `json:"answers"`
+}
+
+func readAnswers(path string) (answersFile, error) {
+	answers := answersFile{Version: answersVersion}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return answers, nil
+	}
+	if err != nil {
+		return answers, errors.New("fail to read answers from " + path + " due to error " + err.Error())
+	}
+
+	if err := json.Unmarshal(content, &answers); err != nil {
+		return answers, errors.New("fail to parse answers from " + path + " due to error " + err.Error())
+	}
+	if answers.Version != answersVersion {
+		return answers, fmt.Errorf("unsupported answers file version %d in %s, expected %d", answers.Version, path, answersVersion)
+	}
+	return answers, nil
+}
+
+// writeAnswers stores the answers sorted by day, part and input, so the file
+// diffs nicely under version control
+func writeAnswers(path string, answers answersFile) error {
+	sort.Slice(answers.Answers, func(i, j int) bool {
+		a, b := answers.Answers[i], answers.Answers[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Input != b.Input {
+			return a.Input < b.Input
+		}
+		return a.Part < b.Part
+	})
+
+	content, err := json.MarshalIndent(answers, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(content, '\n'))
+}
+
+func hashFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// dayInputs returns the input*.txt files found in a day directory, relative to root
+func dayInputs(root string, s solver) []string {
+	var inputs []string
+	matches, _ := filepath.Glob(filepath.Join(root, s.dir, "input*.txt"))
+	for _, match := range matches {
+		inputs = append(inputs, filepath.Join(s.dir, filepath.Base(match)))
+	}
+	return inputs
+}
+
+// verifySolver reruns a solver on each of its inputs having recorded answers and
+// returns the mismatches found. With record, answers of parts not recorded yet are
+// added to answers.
+func verifySolver(root string, s solver, answers *answersFile, record bool) ([]string, error) {
+
+	var mismatches []string
+	matchedHashes := make(map[string]bool)
+
+	for _, input := range dayInputs(root, s) {
+		hash, err := hashFile(filepath.Join(root, input))
+		if err != nil {
+			return nil, err
+		}
+
+		var expected []recordedAnswer
+		for _, a := range answers.Answers {
+			if a.Day == s.day && a.InputHash == hash {
+				expected = append(expected, a)
+			}
+		}
+		if len(expected) == 0 && !record {
+			continue
+		}
+		matchedHashes[hash] = true
+
+		computed, err := runSolver(root, s, filepath.Join(root, input))
+		if err != nil {
+			if len(computed) == 0 {
+				return nil, err
+			}
+			fmt.Printf("day %d %s: %v\n", s.day, input, err)
+		}
+
+		recordedParts := make(map[int]bool)
+		for _, a := range expected {
+			recordedParts[a.Part] = true
+			if computed[a.Part] == a.Answer {
+				fmt.Printf("day %d part %d %s: ok %s\n", a.Day, a.Part, input, a.Answer)
+				continue
+			}
+			mismatch := fmt.Sprintf("day %d part %d %s: expected %s, got %s", a.Day, a.Part, input, a.Answer, computed[a.Part])
+			fmt.Println(mismatch)
+			mismatches = append(mismatches, mismatch)
+		}
+
+		if record {
+			for _, part := range s.sortedParts() {
+				if _, ok := computed[part]; !ok || recordedParts[part] {
+					continue
+				}
+				answers.Answers = append(answers.Answers, recordedAnswer{
+					Day:       s.day,
+					Part:      part,
+					Input:     filepath.ToSlash(input),
+					InputHash: hash,
+					Answer:    computed[part],
+				})
+				fmt.Printf("day %d part %d %s: recorded %s\n", s.day, part, input, computed[part])
+			}
+		}
+	}
+
+	for _, a := range answers.Answers {
+		if a.Day == s.day && !matchedHashes[a.InputHash] {
+			mismatch := fmt.Sprintf("day %d part %d %s: no input matches hash %s", a.Day, a.Part, a.Input, a.InputHash)
+			fmt.Println(mismatch)
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+
+	return mismatches, nil
+}
+
+func runVerify(args []string) error {
+
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	day := flags.Int("day", 0, "only verify this day, every day when 0")
+	root := flags.String("root", defaultRepoRoot(), "repository directory holding the day solvers")
+	answersPath := flags.String("answers", "", "answers file, answers.json of the repository when empty")
+	record := flags.Bool("record", false, "record the answers of parts and inputs missing from the answers file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *answersPath == "" {
+		*answersPath = filepath.Join(*root, "answers.json")
+	}
+
+	answers, err := readAnswers(*answersPath)
+	if err != nil {
+		return err
+	}
+
+	var mismatches []string
+	for _, s := range solvers {
+		if *day != 0 && s.day != *day {
+			continue
+		}
+		solverMismatches, err := verifySolver(*root, s, &answers, *record)
+		if err != nil {
+			return err
+		}
+		mismatches = append(mismatches, solverMismatches...)
+	}
+
+	if *record {
+		if err := writeAnswers(*answersPath, answers); err != nil {
+			return errors.New("fail to write answers to " + *answersPath + " due to error " + err.Error())
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%d answers do not match %s", len(mismatches), *answersPath)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadWriteAnswers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.json")
+
+	// A missing file is an empty registry
+	answers, err := readAnswers(path)
+	if err != nil || len(answers.Answers) != 0 {
+		t.Fatalf("Expected an empty registry, got %v and %v", answers, err)
+	}
+
+	answers.Answers = []recordedAnswer{
+		{Day: 3, Part: 2, Input: "day3/input.txt", InputHash: "b", Answer: "2"},
+		{Day: 1, Part: 2, Input: "day1/input.txt", InputHash: "a", Answer: "1"},
+		{Day: 3, Part: 1, Input: "day3/input.txt", InputHash: "b", Answer: "3"},
+	}
+	if err := writeAnswers(path, answers); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := readAnswers(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []recordedAnswer{
+		{Day: 1, Part: 2, Input: "day1/input.txt", InputHash: "a", Answer: "1"},
+		{Day: 3, Part: 1, Input: "day3/input.txt", InputHash: "b", Answer: "3"},
+		{Day: 3, Part: 2, Input: "day3/input.txt", InputHash: "b", Answer: "2"},
+	}
+	if !reflect.DeepEqual(read.Answers, expected) {
+		t.Errorf("Expected %v, got %v", expected, read.Answers)
+	}
+
+	// Unknown versions are rejected
+	if err := os.WriteFile(path, []byte(`{"version": 99, "answers": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readAnswers(path); err == nil {
+		t.Error("Expected an error for an unsupported version")
+	}
+}
+
+func TestVerifySolver(t *testing.T) {
+	root := defaultRepoRoot()
+	s, err := findSolver(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := hashFile(filepath.Join(root, "day2", "input_test.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := answersFile{
+		Version: answersVersion,
+		Answers: []recordedAnswer{
+			{Day: 2, Part: 1, Input: "day2/input_test.txt", InputHash: hash, Answer: "8"},
+			{Day: 2, Part: 2, Input: "day2/input_test.txt", InputHash: hash, Answer: "1"},
+			{Day: 2, Part: 1, Input: "day2/removed.txt", InputHash: "stale", Answer: "8"},
+		},
+	}
+
+	mismatches, err := verifySolver(root, s, &answers, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) != 2 {
+		t.Errorf("Expected a wrong answer and a stale input, got %v", mismatches)
+	}
+}
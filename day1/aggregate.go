@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// calibration value of a line built from its digits
+const (
+	aggregateFirstLast  = "firstlast"
+	aggregateSum        = "sum"
+	aggregateSequence   = "sequence"
+	aggregateMax        = "max"
+	aggregateMin        = "min"
+	aggregateFirstLastK = "firstlastk"
+)
+
+var aggregateModes = []string{aggregateFirstLast, aggregateSum, aggregateSequence, aggregateMax, aggregateMin, aggregateFirstLastK}
+
+// checkAggregation returns an error when mode is not an aggregation mode, or when k
+// is out of range for the firstlastk mode
+func checkAggregation(mode string, k int) error {
+	if mode == aggregateFirstLastK && k < 1 {
+		return fmt.Errorf("invalid k %d, expected at least 1", k)
+	}
+	for _, known := range aggregateModes {
+		if mode == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown aggregation %q, expected one of %s", mode, strings.Join(aggregateModes, ", "))
+}
+
+// tokenizeDigits returns the digits of a line from left to right, spelled out digits
+// included when words is true. Overlapping words all count, so "twone" gives 2 then
+// 1, and keepFirstAndLast keeps the first and last tokens. Spelled out digits are
+// lowercase only.
+func tokenizeDigits(line string, words bool) []string {
+
+	var digits []string
+
+	for i, char := range line {
+		if unicode.IsDigit(char) {
+			digits = append(digits, string(char))
+			continue
+		}
+		if !words {
+			continue
+		}
+		for word, digit := range numberMap {
+			if strings.HasPrefix(line[i:], word) {
+				digits = append(digits, digit)
+				break
+			}
+		}
+	}
+
+	return digits
+}
+
+// aggregateDigits reduces the digits of a line to the digits of its calibration
+// value, k being the number of digits kept at each end by the firstlastk mode
+func aggregateDigits(digits []string, mode string, k int) (string, error) {
+
+	if err := checkAggregation(mode, k); err != nil {
+		return "", err
+	}
+	if len(digits) == 0 {
+		return "", errors.New("no digit found")
+	}
+
+	switch mode {
+	case aggregateFirstLast:
+		return digits[0] + digits[len(digits)-1], nil
+	case aggregateSum:
+		sum := 0
+		for _, digit := range digits {
+			if len(digit) != 1 || digit[0] < '0' || digit[0] > '9' {
+				return "", fmt.Errorf("fail to sum digit %q, only 0 to 9 are supported", digit)
+			}
+			sum += int(digit[0] - '0')
+		}
+		return strconv.Itoa(sum), nil
+	case aggregateSequence:
+		return strings.Join(digits, ""), nil
+	case aggregateMax, aggregateMin:
+		kept := digits[0]
+		for _, digit := range digits[1:] {
+			if (mode == aggregateMax && digit > kept) || (mode == aggregateMin && digit < kept) {
+				kept = digit
+			}
+		}
+		return kept, nil
+	default:
+		// firstlastk, the only mode left once checked
+		if k > len(digits) {
+			k = len(digits)
+		}
+		return strings.Join(digits[:k], "") + strings.Join(digits[len(digits)-k:], ""), nil
+	}
+}
+
+// aggregateLines returns the calibration value of each line for an aggregation mode.
+// Values are big integers, as a long digit sequence overflows an int.
+func aggregateLines(lines []string, words bool, mode string, k int) ([]*big.Int, error) {
+
+	var values []*big.Int
+
+	for lineNb, line := range lines {
+		numberStr, err := aggregateDigits(tokenizeDigits(line, words), mode, k)
+		if err != nil {
+			return values, fmt.Errorf("line %d: %v", lineNb+1, err)
+		}
+		value, ok := new(big.Int).SetString(numberStr, 10)
+		if !ok {
+			return values, fmt.Errorf("line %d: fail to transform keeped digits %s to integer", lineNb+1, numberStr)
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// iterate a big ints slice and sum it
+func sumBigInts(ints []*big.Int) *big.Int {
+	sum := new(big.Int)
+	for _, i := range ints {
+		sum.Add(sum, i)
+	}
+	return sum
+}
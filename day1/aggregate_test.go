@@ -0,0 +1,106 @@
+package main
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenizeDigits(t *testing.T) {
+	testCases := []struct {
+		line     string
+		words    bool
+		expected []string
+	}{
+		{"a1b2c3", false, []string{"1", "2", "3"}},
+		{"twone3", false, []string{"3"}},
+		{"twone3", true, []string{"2", "1", "3"}},
+		{"eightwo", true, []string{"8", "2"}},
+		{"oneight", true, []string{"1", "8"}},
+		{"SevEn4", true, []string{"4"}},
+		{"ONE2", true, []string{"2"}},
+		{"xyz", true, nil},
+	}
+
+	for _, testCase := range testCases {
+		result := tokenizeDigits(testCase.line, testCase.words)
+		if !reflect.DeepEqual(result, testCase.expected) {
+			t.Errorf("For line %s and words %v, expected %v, but got %v", testCase.line, testCase.words, testCase.expected, result)
+		}
+	}
+}
+
+func TestAggregateDigits(t *testing.T) {
+	digits := []string{"4", "9", "8", "7", "2"}
+	testCases := []struct {
+		mode     string
+		k        int
+		expected string
+	}{
+		{aggregateFirstLast, 0, "42"},
+		{aggregateSum, 0, "30"},
+		{aggregateSequence, 0, "49872"},
+		{aggregateMax, 0, "9"},
+		{aggregateMin, 0, "2"},
+		{aggregateFirstLastK, 1, "42"},
+		{aggregateFirstLastK, 2, "4972"},
+		{aggregateFirstLastK, 9, "4987249872"},
+	}
+
+	for _, testCase := range testCases {
+		result, err := aggregateDigits(digits, testCase.mode, testCase.k)
+		if err != nil {
+			t.Errorf("Unexpected error for mode %s: %v", testCase.mode, err)
+		}
+		if result != testCase.expected {
+			t.Errorf("For mode %s and k %d, expected %s, but got %s", testCase.mode, testCase.k, testCase.expected, result)
+		}
+	}
+
+	if _, err := aggregateDigits(nil, aggregateSum, 0); err == nil {
+		t.Error("Expected an error without digits")
+	}
+	if _, err := aggregateDigits(digits, "median", 0); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+	if _, err := aggregateDigits(digits, aggregateFirstLastK, 0); err == nil {
+		t.Error("Expected an error for k 0")
+	}
+	if _, err := aggregateDigits(tokenizeDigits("x٣y1", false), aggregateSum, 0); err == nil {
+		t.Error("Expected an error for a non ASCII digit")
+	}
+}
+
+func TestCheckAggregation(t *testing.T) {
+	for _, mode := range aggregateModes {
+		if err := checkAggregation(mode, 2); err != nil {
+			t.Errorf("Unexpected error for mode %s: %v", mode, err)
+		}
+	}
+	if err := checkAggregation("median", 2); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+	if err := checkAggregation(aggregateFirstLastK, 0); err == nil {
+		t.Error("Expected an error for k 0")
+	}
+}
+
+func TestAggregateLines(t *testing.T) {
+	long := strings.Repeat("9", 40)
+	values, err := aggregateLines([]string{"a" + long + "b", "one2"}, true, aggregateSequence, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := new(big.Int).SetString(long, 10)
+	expected.Add(expected, big.NewInt(12))
+	if sum := sumBigInts(values); sum.Cmp(expected) != 0 {
+		t.Errorf("Expected %s, got %s", expected, sum)
+	}
+
+	_, err = aggregateLines([]string{"1", "none"}, false, aggregateSum, 0)
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("Expected an error on line 2, got %v", err)
+	}
+}
@@ -2,13 +2,16 @@ package main
 
 import (
 	"bufio"
+	_ "embed"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
-	"regexp"
+	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
-	"unicode"
 )
 
 var numberMap = map[string]string{
@@ -23,103 +26,76 @@ var numberMap = map[string]string{
 	"nine":  "9",
 }
 
+//go:embed input_test.txt
+var exampleInput string
+
+// the part 2 example has lines without any digit, so part 1 has its own example
+//
+//go:embed input_test_part1.txt
+var examplePart1Input string
+
+// defaultInputPath returns the input.txt stored next to this source file, so the
+// solver finds its puzzle input whatever the current working directory is
+func defaultInputPath() string {
+	_, source, _, ok := runtime.Caller(0)
+	if ok {
+		path := filepath.Join(filepath.Dir(source), "input.txt")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return "./input.txt"
+}
+
 // read each line of file and add to a slice
 func readLines(filename string) ([]string, error) {
-	lines := make([]string, 0)
 	file, err := os.Open(filename)
 	if err != nil {
-		return lines, errors.New("fail to read lines from " + filename + " due to error " + err.Error())
+		return make([]string, 0), errors.New("fail to read lines from " + filename + " due to error " + err.Error())
 	}
 	defer file.Close()
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
-	}
-	return lines, nil
+	return readLinesFrom(file)
 }
 
-func reverseStr(s string) string {
-	runes := []rune(s)
-	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
-		runes[i], runes[j] = runes[j], runes[i]
-	}
-	return string(runes)
-}
-
-// replaceRegularSpelledOutNumber replaces spelled-out numbers in a given string
-// with their corresponding digits using a predefined map. From left to right.
-// For example, if the input string contains "eightwo", the function will replace
-// it with "8wo" based on the predefined numberMap.
-func replaceRegularSpelledOutNumber(str string) string {
-
-	// Regular expression to match all number words in the map
-	var numberWords []string
-	for word := range numberMap {
-		numberWords = append(numberWords, word)
+// read each line of a reader and add to a slice
+func readLinesFrom(reader io.Reader) ([]string, error) {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
 	}
-
-	// Replace function that uses the map to replace words with digits
-	replaceFunc := func(match string) string {
-
-		lowerMatch := strings.ToLower(match) // Ensure case-insensitivity
-		if digit, exists := numberMap[lowerMatch]; exists {
-			return digit
-		}
-		return match
+	if err := scanner.Err(); err != nil {
+		return lines, errors.New("fail to read lines due to error " + err.Error())
 	}
-
-	pattern := `(` + strings.Join(numberWords, "|") + `)`
-	re := regexp.MustCompile(pattern)
-
-	return re.ReplaceAllStringFunc(str, replaceFunc)
+	return lines, nil
 }
 
-// replaceReverseSpelledOutNumber replaces spelled-out numbers in a given string
-// with their corresponding digits using a predefined map. From right to left.
-// For example, if the input string contains "eightwo", the function will replace
-// it with "eigh2" based on the predefined numberMap.
-func replaceReverseSpelledOutNumber(str string) string {
-
-	// Regular expression to match all number words in the map
-	var numberWords []string
-	for word := range numberMap {
-		numberWords = append(numberWords, reverseStr(word))
+// loadInput returns the puzzle lines, read from the example bundled with the
+// solver, from stdin when inputPath is "-", or from the inputPath file
+func loadInput(inputPath string, example bool) ([]string, error) {
+	if example {
+		return readLinesFrom(strings.NewReader(exampleInput))
 	}
-
-	// Replace function that uses the map to replace words with digits
-	replaceFunc := func(match string) string {
-
-		lowerMatch := strings.ToLower(match) // Ensure case-insensitivity
-		if digit, exists := numberMap[reverseStr(lowerMatch)]; exists {
-			return digit
-		}
-		return match
+	if inputPath == "-" {
+		return readLinesFrom(os.Stdin)
 	}
-
-	pattern := `(` + strings.Join(numberWords, "|") + `)`
-	re := regexp.MustCompile(pattern)
-
-	return reverseStr(re.ReplaceAllStringFunc(reverseStr(str), replaceFunc))
+	return readLines(inputPath)
 }
 
-// get first string digit
-func findFirstDigit(input string) string {
-	for _, char := range input {
-		if unicode.IsDigit(char) {
-			return string(char)
-		}
-	}
-	return ""
+// keep only the first and the last digit character of string, ignoring spelled out
+// digits
+func keepFirstAndLastDigits(line string) string {
+	// no digit keeps nothing
+	kept, _ := aggregateDigits(tokenizeDigits(line, false), aggregateFirstLast, 0)
+	return kept
 }
 
 // keep only the first and the last digit character of string. Taking in account that
 // some of the digits are actually spelled out with letters
 func keepFirstAndLast(line string) string {
-
-	first := findFirstDigit(replaceRegularSpelledOutNumber(line))
-	last := findFirstDigit(reverseStr(replaceReverseSpelledOutNumber(line)))
-
-	return first + last
+	// no digit keeps nothing
+	kept, _ := aggregateDigits(tokenizeDigits(line, true), aggregateFirstLast, 0)
+	return kept
 }
 
 // iterate an ints slice and sum it
@@ -131,14 +107,16 @@ func sumInts(ints []int) int {
 	return sum
 }
 
-func lines2Ints(lines []string) ([]int, error) {
+// lines2Ints turns each line into its calibration value, made of the digits kept by
+// the keep function
+func lines2Ints(lines []string, keep func(string) string) ([]int, error) {
 
 	var ints []int
 
 	for _, line := range lines {
 
 		//get first and last number
-		numberStr := keepFirstAndLast(line)
+		numberStr := keep(line)
 		number, err := strconv.Atoi(numberStr)
 		if err != nil {
 			return ints, errors.New("fail to transform keeped digits " + numberStr + " to integer with error " + err.Error())
@@ -150,20 +128,121 @@ func lines2Ints(lines []string) ([]int, error) {
 
 }
 
+// solvePart1 sums the calibration values made of digits only
+func solvePart1(lines []string) (int, error) {
+	ints, err := lines2Ints(lines, keepFirstAndLastDigits)
+	if err != nil {
+		return 0, err
+	}
+	return sumInts(ints), nil
+}
+
+// solvePart2 sums the calibration values made of digits and spelled out digits
+func solvePart2(lines []string) (int, error) {
+	ints, err := lines2Ints(lines, keepFirstAndLast)
+	if err != nil {
+		return 0, err
+	}
+	return sumInts(ints), nil
+}
+
 func main() {
 
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := runGenerate(os.Args[2:]); err != nil {
+			panic(err.Error())
+		}
+		return
+	}
+
+	inputPath := flag.String("input", defaultInputPath(), "puzzle input file, or - to read stdin")
+	example := flag.Bool("example", false, "solve the example bundled with the solver instead of the input")
+	part := flag.Int("part", 0, "part to solve, 1 or 2, both when 0")
+	workers := flag.Int("workers", runtime.NumCPU(), "workers processing chunks of the input file concurrently, 1 to read it line by line")
+	aggregate := flag.String("aggregate", "", "calibration value of a line: "+strings.Join(aggregateModes, ", ")+", the puzzle one when empty")
+	k := flag.Int("k", 2, "digits kept at each end of a line by the firstlastk aggregation")
+	flag.Parse()
+
+	if *part < 0 || *part > 2 {
+		panic("invalid part " + strconv.Itoa(*part) + ", expected 1 or 2")
+	}
+	if *aggregate != "" {
+		if err := checkAggregation(*aggregate, *k); err != nil {
+			panic(err.Error())
+		}
+	}
+
+	// an input file is processed by chunks, without loading all its lines in memory
+	chunked := *aggregate == "" && *workers > 1 && !*example && *inputPath != "-"
+
 	//read input file
-	lines, err := readLines("./input.txt")
-	if err != nil {
-		panic(err.Error())
+	var lines []string
+	if !chunked {
+		var err error
+		lines, err = loadInput(*inputPath, *example)
+		if err != nil {
+			panic(err.Error())
+		}
 	}
 
-	//get the calibration value
-	ints, err := lines2Ints(lines)
-	if err != nil {
-		panic(err.Error())
+	// solve sums the calibration values of a part, part 2 including spelled out digits
+	solve := func(part int) (string, error) {
+		keep := keepFirstAndLastDigits
+		if part == 2 {
+			keep = keepFirstAndLast
+		}
+
+		partLines := lines
+		if part == 1 && *example {
+			partLines, _ = readLinesFrom(strings.NewReader(examplePart1Input))
+		}
+
+		if *aggregate != "" {
+			values, err := aggregateLines(partLines, part == 2, *aggregate, *k)
+			if err != nil {
+				return "", err
+			}
+			return sumBigInts(values).String(), nil
+		}
+
+		var sum int
+		var err error
+		if chunked {
+			sum, err = sumCalibrationFile(*inputPath, keep, *workers)
+		} else if part == 1 {
+			sum, err = solvePart1(partLines)
+		} else {
+			sum, err = solvePart2(partLines)
+		}
+		return strconv.Itoa(sum), err
+	}
+
+	labels := map[int]string{
+		1: "sum of calibration values",
+		2: "sum of calibration values with spelled out digits",
+	}
+	if *aggregate != "" {
+		labels[1] = "sum of " + *aggregate + " calibration values"
+		labels[2] = "sum of " + *aggregate + " calibration values with spelled out digits"
 	}
 
-	//sum all the calibration values
-	fmt.Println(sumInts(ints))
+	failed := false
+
+	for _, p := range []int{1, 2} {
+		if *part != 0 && *part != p {
+			continue
+		}
+
+		sum, err := solve(p)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Part "+strconv.Itoa(p)+" - "+err.Error())
+			failed = true
+			continue
+		}
+		fmt.Println("Part "+strconv.Itoa(p)+" - "+labels[p]+": ", sum)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
 }
@@ -3,7 +3,9 @@ package main
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -41,64 +43,89 @@ func TestReadLines(t *testing.T) {
 	}
 }
 
-func TestReplaceRegularSpelledOutNumber(t *testing.T) {
+func TestLoadInput(t *testing.T) {
+	// Create a temporary file with sample content
+	tmpfile, err := os.CreateTemp("", "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name()) // clean up
 
-	input := "eightwo"
-	expected := "8wo"
+	if _, err := tmpfile.WriteString("Line 1\nLine 2"); err != nil {
+		t.Fatal(err)
+	}
+	tmpfile.Close()
 
-	actual := replaceRegularSpelledOutNumber(input)
-	if actual != expected {
-		t.Errorf("Expected %s, got %s", expected, actual)
+	// Test reading the input from a file path
+	lines, err := loadInput(tmpfile.Name(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lines, []string{"Line 1", "Line 2"}) {
+		t.Errorf("Expected file lines, but got %v", lines)
 	}
 
+	// Test reading the bundled example, which must ignore the input path
+	lines, err = loadInput("nonexistentfile.txt", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) == 0 {
+		t.Error("Expected the bundled example lines, but got none")
+	}
 }
 
-func TestReplaceReverseSpelledOutNumber(t *testing.T) {
-
-	input := "eightwo"
-	expected := "eigh2"
-
-	actual := replaceReverseSpelledOutNumber(input)
-	if actual != expected {
-		t.Errorf("Expected %s, got %s", expected, actual)
+func TestDefaultInputPath(t *testing.T) {
+	path := defaultInputPath()
+	if filepath.Base(path) != "input.txt" {
+		t.Errorf("Expected a path to input.txt, but got %s", path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected default input %s to exist, but got %v", path, err)
 	}
-
 }
 
-func TestReverseStr(t *testing.T) {
+func TestKeepFirstAndLast(t *testing.T) {
 
 	input := "eightwo"
-	expected := "owthgie"
+	expected := "82"
 
-	actual := reverseStr(input)
+	actual := keepFirstAndLast(input)
 	if actual != expected {
-		t.Errorf("Expected %s, got %s", expected, actual)
+		t.Errorf("Expected %v, got %v", expected, actual)
 	}
 
 }
 
-func TestFindFirstDigit(t *testing.T) {
+func TestKeepFirstAndLastDigits(t *testing.T) {
 
-	input := "dfdsf8two"
-	expected := "8"
+	input := "eightwo3four5six"
+	expected := "35"
 
-	actual := findFirstDigit(input)
+	actual := keepFirstAndLastDigits(input)
 	if actual != expected {
 		t.Errorf("Expected %v, got %v", expected, actual)
 	}
 
 }
 
-func TestKeepFirstAndLast(t *testing.T) {
+func TestSolveParts(t *testing.T) {
+	lines := []string{"two1nine", "eightwo3three", "abcone2threexyz", "treb7uchet"}
 
-	input := "eightwo"
-	expected := "82"
+	part1, err := solvePart1(lines)
+	if err != nil || part1 != 11+33+22+77 {
+		t.Errorf("Expected part 1 %d, got %d and error %v", 11+33+22+77, part1, err)
+	}
 
-	actual := keepFirstAndLast(input)
-	if actual != expected {
-		t.Errorf("Expected %v, got %v", expected, actual)
+	part2, err := solvePart2(lines)
+	if err != nil || part2 != 29+83+13+77 {
+		t.Errorf("Expected part 2 %d, got %d and error %v", 29+83+13+77, part2, err)
 	}
 
+	// Part 1 needs a digit on every line
+	if _, err := solvePart1([]string{"eightwothree"}); err == nil {
+		t.Error("Expected an error for a line without digits")
+	}
 }
 
 func TestLines2Ints(t *testing.T) {
@@ -113,7 +140,7 @@ func TestLines2Ints(t *testing.T) {
 	}
 
 	for _, testCase := range testCases {
-		result, err := lines2Ints(testCase.inputLines)
+		result, err := lines2Ints(testCase.inputLines, keepFirstAndLast)
 
 		if !reflect.DeepEqual(err, testCase.expectedErr) {
 			t.Errorf("For input lines %v, expected error %v, but got %v", testCase.inputLines, testCase.expectedErr, err)
@@ -124,3 +151,61 @@ func TestLines2Ints(t *testing.T) {
 		}
 	}
 }
+
+// benchmarkInput returns the raw puzzle input used by the benchmarks
+func benchmarkInput(b *testing.B) string {
+	content, err := os.ReadFile(defaultInputPath())
+	if err != nil {
+		b.Skip("no puzzle input to benchmark: " + err.Error())
+	}
+	return string(content)
+}
+
+// benchmarkLines splits the benchmark input into lines
+func benchmarkLines(b *testing.B, input string) []string {
+	lines, err := readLinesFrom(strings.NewReader(input))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return lines
+}
+
+func BenchmarkPart1(b *testing.B) {
+	input := benchmarkInput(b)
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchmarkLines(b, input)
+		}
+	})
+
+	lines := benchmarkLines(b, input)
+	b.Run("solve", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := solvePart1(lines); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkPart2(b *testing.B) {
+	input := benchmarkInput(b)
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchmarkLines(b, input)
+		}
+	})
+
+	lines := benchmarkLines(b, input)
+	b.Run("solve", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := solvePart2(lines); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+var referenceWords = []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+
+// referenceDigitAt returns the digit starting at byte i of line, spelled out or not
+func referenceDigitAt(line string, i int) string {
+	char, _ := utf8.DecodeRuneInString(line[i:])
+	if unicode.IsDigit(char) {
+		return string(char)
+	}
+	for digit, word := range referenceWords {
+		if strings.HasPrefix(line[i:], word) {
+			return string(rune('1' + digit))
+		}
+	}
+	return ""
+}
+
+// referenceKeepFirstAndLast is a brute-force oracle for keepFirstAndLast, trying
+// every position from each end of the line
+func referenceKeepFirstAndLast(line string) string {
+	first, last := "", ""
+	for i := 0; i < len(line) && first == ""; i++ {
+		first = referenceDigitAt(line, i)
+	}
+	for i := len(line) - 1; i >= 0 && last == ""; i-- {
+		last = referenceDigitAt(line, i)
+	}
+	return first + last
+}
+
+func FuzzKeepFirstAndLast(f *testing.F) {
+	for _, seed := range []string{"", "eightwo", "twone3four", "4nineeightseven2", "zoneight234", "7pqrstsixteen", "treb7uchet", "oneighthreeightwo"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		expected := referenceKeepFirstAndLast(line)
+		if actual := keepFirstAndLast(line); actual != expected {
+			t.Errorf("For line %q, expected %q, but got %q", line, expected, actual)
+		}
+	})
+}
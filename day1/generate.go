@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// overlapping spelled out digits, with the digits they stand for
+var overlapWords = map[string][]string{
+	"twone":     {"2", "1"},
+	"eightwo":   {"8", "2"},
+	"oneight":   {"1", "8"},
+	"threeight": {"3", "8"},
+	"fiveight":  {"5", "8"},
+	"nineight":  {"9", "8"},
+	"sevenine":  {"7", "9"},
+	"eighthree": {"8", "3"},
+}
+
+// letters found in no number word, so noise never spells out a digit by accident
+const defaultNoise = "abcdjklmpqyz"
+
+type generatorConfig struct {
+	seed      int64
+	lines     int
+	minLength int
+	maxLength int
+	// relative weights of the tokens composing a line
+	digitWeight   int
+	wordWeight    int
+	overlapWeight int
+	noiseWeight   int
+	noise         string
+}
+
+// expectedValue is the calibration value of a generated line, for both parts
+type expectedValue struct {
+	part1 int
+	part2 int
+}
+
+func (conf generatorConfig) validate() error {
+	if conf.lines < 0 {
+		return fmt.Errorf("invalid lines %d", conf.lines)
+	}
+	if conf.minLength < 1 || conf.maxLength < conf.minLength {
+		return fmt.Errorf("invalid line length range %d to %d", conf.minLength, conf.maxLength)
+	}
+	if conf.digitWeight < 0 || conf.wordWeight < 0 || conf.overlapWeight < 0 || conf.noiseWeight < 0 {
+		return errors.New("token weights cannot be negative")
+	}
+	if conf.digitWeight+conf.wordWeight+conf.overlapWeight+conf.noiseWeight == 0 {
+		return errors.New("at least one token weight must be positive")
+	}
+	if conf.noiseWeight > 0 && conf.noise == "" {
+		return errors.New("noise letters are needed with a positive noise weight")
+	}
+	for _, char := range conf.noise {
+		for word := range numberMap {
+			if strings.ContainsRune(word, char) {
+				return fmt.Errorf("noise letter %q appears in %q and could spell out a digit", char, word)
+			}
+		}
+		if char >= '0' && char <= '9' {
+			return fmt.Errorf("noise letter %q is a digit", char)
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a map in order, so the generated document only
+// depends on the seed
+func sortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// generateLine builds a line from random tokens and returns it with the digits it
+// holds, digits only and spelled out digits included. Every line holds at least
+// one digit character, so both parts have a value.
+func generateLine(rnd *rand.Rand, conf generatorConfig, words []string, overlaps []string) (string, []string, []string) {
+
+	var builder strings.Builder
+	var digits, allDigits []string
+
+	length := conf.minLength + rnd.Intn(conf.maxLength-conf.minLength+1)
+	total := conf.digitWeight + conf.wordWeight + conf.overlapWeight + conf.noiseWeight
+
+	for builder.Len() < length || len(digits) == 0 {
+		pick := rnd.Intn(total)
+
+		switch {
+		// a line long enough but still without digit ends with one
+		case pick < conf.digitWeight || builder.Len() >= length:
+			digit := strconv.Itoa(1 + rnd.Intn(9))
+			builder.WriteString(digit)
+			digits = append(digits, digit)
+			allDigits = append(allDigits, digit)
+		case pick < conf.digitWeight+conf.wordWeight:
+			word := words[rnd.Intn(len(words))]
+			builder.WriteString(word)
+			allDigits = append(allDigits, numberMap[word])
+		case pick < conf.digitWeight+conf.wordWeight+conf.overlapWeight:
+			overlap := overlaps[rnd.Intn(len(overlaps))]
+			builder.WriteString(overlap)
+			allDigits = append(allDigits, overlapWords[overlap]...)
+		default:
+			builder.WriteByte(conf.noise[rnd.Intn(len(conf.noise))])
+		}
+	}
+
+	return builder.String(), digits, allDigits
+}
+
+// generateCalibration builds a calibration document from a seed, with the expected
+// value of each line
+func generateCalibration(conf generatorConfig) ([]string, []expectedValue, error) {
+
+	if err := conf.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	rnd := rand.New(rand.NewSource(conf.seed))
+	words := sortedKeys(numberMap)
+	overlaps := sortedKeys(overlapWords)
+
+	var lines []string
+	var expected []expectedValue
+
+	for i := 0; i < conf.lines; i++ {
+		line, digits, allDigits := generateLine(rnd, conf, words, overlaps)
+
+		part1, _ := strconv.Atoi(digits[0] + digits[len(digits)-1])
+		part2, _ := strconv.Atoi(allDigits[0] + allDigits[len(allDigits)-1])
+
+		lines = append(lines, line)
+		expected = append(expected, expectedValue{part1, part2})
+	}
+
+	return lines, expected, nil
+}
+
+// writeGenerated writes the lines to path, and the expected value of each line
+// followed by the totals to path.expected
+func writeGenerated(path string, lines []string, expected []expectedValue) error {
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		return errors.New("fail to write generated lines to " + path + " due to error " + err.Error())
+	}
+
+	file, err := os.Create(path + ".expected")
+	if err != nil {
+		return errors.New("fail to write expected values due to error " + err.Error())
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	fmt.Fprintln(writer, "# line\tpart1\tpart2")
+	total1, total2 := 0, 0
+	for i, value := range expected {
+		fmt.Fprintf(writer, "%d\t%d\t%d\n", i+1, value.part1, value.part2)
+		total1 += value.part1
+		total2 += value.part2
+	}
+	fmt.Fprintf(writer, "total\t%d\t%d\n", total1, total2)
+
+	if err := writer.Flush(); err != nil {
+		return errors.New("fail to write expected values due to error " + err.Error())
+	}
+	return file.Close()
+}
+
+func runGenerate(args []string) error {
+
+	conf := generatorConfig{}
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	out := flags.String("out", "generated.txt", "file receiving the document, expected values going to <out>.expected")
+	flags.Int64Var(&conf.seed, "seed", 1, "seed of the generator, the same seed giving the same document")
+	flags.IntVar(&conf.lines, "lines", 1000, "number of lines")
+	flags.IntVar(&conf.minLength, "min-length", 5, "minimal line length")
+	flags.IntVar(&conf.maxLength, "max-length", 40, "maximal line length, a last token may exceed it")
+	flags.IntVar(&conf.digitWeight, "digits", 2, "weight of digit characters")
+	flags.IntVar(&conf.wordWeight, "words", 2, "weight of spelled out digits")
+	flags.IntVar(&conf.overlapWeight, "overlaps", 1, "weight of overlapping spelled out digits such as twone")
+	flags.IntVar(&conf.noiseWeight, "noise", 5, "weight of noise letters")
+	flags.StringVar(&conf.noise, "noise-letters", defaultNoise, "letters used as noise, none of them may appear in a number word")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	lines, expected, err := generateCalibration(conf)
+	if err != nil {
+		return err
+	}
+	if err := writeGenerated(*out, lines, expected); err != nil {
+		return err
+	}
+
+	fmt.Println("generated", len(lines), "lines to", *out, "and", *out+".expected")
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestGenerateCalibration(t *testing.T) {
+	testCases := []struct {
+		name string
+		conf generatorConfig
+		// every generated line must match it
+		line *regexp.Regexp
+	}{
+		{
+			"mixed tokens",
+			generatorConfig{seed: 42, lines: 500, minLength: 1, maxLength: 30, digitWeight: 1, wordWeight: 3, overlapWeight: 2, noiseWeight: 4, noise: defaultNoise},
+			regexp.MustCompile(`^[1-9a-z]*[1-9][1-9a-z]*$`),
+		},
+		{
+			// the only digit character is the one ending the line
+			"overlapping words only",
+			generatorConfig{seed: 7, lines: 200, minLength: 10, maxLength: 40, overlapWeight: 1},
+			regexp.MustCompile(`^(twone|eightwo|oneight|threeight|fiveight|nineight|sevenine|eighthree)+[1-9]$`),
+		},
+		{
+			"digits in noise",
+			generatorConfig{seed: 3, lines: 200, minLength: 1, maxLength: 5, digitWeight: 1, noiseWeight: 9, noise: "kpq"},
+			regexp.MustCompile(`^[kpq]*[1-9][1-9kpq]*$`),
+		},
+		{
+			"single digit lines",
+			generatorConfig{seed: 1, lines: 100, minLength: 1, maxLength: 1, digitWeight: 1},
+			regexp.MustCompile(`^[1-9]$`),
+		},
+	}
+
+	for _, testCase := range testCases {
+		lines, expected, err := generateCalibration(testCase.conf)
+		if err != nil {
+			t.Fatalf("For %s, expected no error, got %v", testCase.name, err)
+		}
+		if len(lines) != testCase.conf.lines || len(expected) != testCase.conf.lines {
+			t.Fatalf("For %s, expected %d lines, got %d lines and %d values", testCase.name, testCase.conf.lines, len(lines), len(expected))
+		}
+
+		// The solver must find the value recorded for each line
+		for i, line := range lines {
+			if !testCase.line.MatchString(line) {
+				t.Errorf("For %s, expected line %q to match %s", testCase.name, line, testCase.line)
+			}
+			part1, err := lines2Ints([]string{line}, keepFirstAndLastDigits)
+			if err != nil || part1[0] != expected[i].part1 {
+				t.Errorf("For %s and line %s, expected part 1 %d, got %v and error %v", testCase.name, line, expected[i].part1, part1, err)
+			}
+			part2, err := lines2Ints([]string{line}, keepFirstAndLast)
+			if err != nil || part2[0] != expected[i].part2 {
+				t.Errorf("For %s and line %s, expected part 2 %d, got %v and error %v", testCase.name, line, expected[i].part2, part2, err)
+			}
+		}
+
+		// The same seed gives the same document
+		again, _, err := generateCalibration(testCase.conf)
+		if err != nil || !reflect.DeepEqual(lines, again) {
+			t.Errorf("For %s, expected the same lines for the same seed, got error %v", testCase.name, err)
+		}
+	}
+}
+
+func TestGenerateCalibrationSolved(t *testing.T) {
+	conf := generatorConfig{seed: 5, lines: 300, minLength: 5, maxLength: 20, digitWeight: 1, wordWeight: 2, overlapWeight: 2, noiseWeight: 3, noise: defaultNoise}
+	lines, expected, err := generateCalibration(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "generated.txt")
+	if err := writeGenerated(path, lines, expected); err != nil {
+		t.Fatal(err)
+	}
+
+	// Solving the written document gives the totals of the expected file
+	read, err := readLines(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part1, err := solvePart1(read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part2, err := solvePart2(read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedLines, err := readLines(path + ".expected")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total := fmt.Sprintf("total\t%d\t%d", part1, part2); expectedLines[len(expectedLines)-1] != total {
+		t.Errorf("Expected %q, but got %q", expectedLines[len(expectedLines)-1], total)
+	}
+}
+
+func TestGeneratorNoise(t *testing.T) {
+	testCases := []struct {
+		noise string
+		valid bool
+	}{
+		{defaultNoise, true},
+		{"kpq", true},
+		// o is a letter of one, two and four
+		{"ko", false},
+		{"ab1", false},
+	}
+
+	for _, testCase := range testCases {
+		conf := generatorConfig{lines: 1, minLength: 1, maxLength: 1, noiseWeight: 1, noise: testCase.noise}
+		if err := conf.validate(); (err == nil) != testCase.valid {
+			t.Errorf("For noise %q, expected valid %t, but got error %v", testCase.noise, testCase.valid, err)
+		}
+	}
+}
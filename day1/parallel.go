@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// longest line accepted when scanning a chunk
+const maxLineLength = 1 << 20
+
+// smallest chunk worth handing to a worker
+const minChunkSize = 64 * 1024
+
+// chunk is a byte range [start, end) of the input, starting at the beginning of a
+// line and ending just after a newline or at the end of the input
+type chunk struct {
+	start int64
+	end   int64
+}
+
+// chunkResult is the outcome of a chunk, with the line numbers local to the chunk
+type chunkResult struct {
+	sum     int
+	lines   int
+	errLine int
+	err     error
+}
+
+// splitChunks splits size bytes of input into chunks of about chunkSize bytes,
+// moving each boundary forward to the byte following the next newline so that no
+// line is shared between two chunks
+func splitChunks(input io.ReaderAt, size int64, chunkSize int64) ([]chunk, error) {
+
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+
+	var chunks []chunk
+	buffer := make([]byte, 4096)
+	start := int64(0)
+
+	for start < size {
+		end := start + chunkSize
+		if end >= size {
+			chunks = append(chunks, chunk{start, size})
+			break
+		}
+
+		// look for the end of the line containing the tentative boundary
+		for end < size {
+			n, err := input.ReadAt(buffer, end-1)
+			if n > 0 {
+				if i := bytes.IndexByte(buffer[:n], '\n'); i >= 0 {
+					end += int64(i)
+					break
+				}
+				end += int64(n)
+			}
+			if err == io.EOF {
+				end = size
+				break
+			}
+			if err != nil {
+				return nil, errors.New("fail to split input in chunks due to error " + err.Error())
+			}
+		}
+		if end > size {
+			end = size
+		}
+
+		chunks = append(chunks, chunk{start, end})
+		start = end
+	}
+
+	return chunks, nil
+}
+
+// processChunk sums the calibration values of the lines of a chunk, stopping at the
+// first line that cannot be turned into a value
+func processChunk(input io.ReaderAt, c chunk, keep func(string) string) chunkResult {
+
+	var result chunkResult
+
+	scanner := bufio.NewScanner(io.NewSectionReader(input, c.start, c.end-c.start))
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+	for scanner.Scan() {
+		result.lines++
+
+		numberStr := keep(scanner.Text())
+		number, err := strconv.Atoi(numberStr)
+		if err != nil {
+			result.errLine = result.lines
+			result.err = errors.New("fail to transform keeped digits " + numberStr + " to integer with error " + err.Error())
+			return result
+		}
+		result.sum += number
+	}
+	if err := scanner.Err(); err != nil {
+		result.errLine = result.lines + 1
+		result.err = errors.New("fail to read line due to error " + err.Error())
+	}
+
+	return result
+}
+
+// sumCalibrationChunks processes the chunks with a pool of workers and adds their
+// partial sums in chunk order. When chunks fail, the error of the first failing line
+// of the input is returned, numbered from the start of the input.
+func sumCalibrationChunks(input io.ReaderAt, chunks []chunk, keep func(string) string, workers int) (int, error) {
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]chunkResult, len(chunks))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = processChunk(input, chunks[i], keep)
+			}
+		}()
+	}
+	for i := range chunks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	sum := 0
+	previousLines := 0
+	for _, result := range results {
+		if result.err != nil {
+			return 0, fmt.Errorf("line %d: %v", previousLines+result.errLine, result.err)
+		}
+		sum += result.sum
+		previousLines += result.lines
+	}
+
+	return sum, nil
+}
+
+// sumCalibrationFile sums the calibration values of a file, processing chunks of
+// it concurrently with the given number of workers
+func sumCalibrationFile(filename string, keep func(string) string, workers int) (int, error) {
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, errors.New("fail to read lines from " + filename + " due to error " + err.Error())
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, errors.New("fail to read lines from " + filename + " due to error " + err.Error())
+	}
+
+	// several chunks per worker, so a slow chunk does not hold the others
+	chunkSize := info.Size() / int64(4*workers)
+	if chunkSize < minChunkSize {
+		chunkSize = minChunkSize
+	}
+
+	chunks, err := splitChunks(file, info.Size(), chunkSize)
+	if err != nil {
+		return 0, err
+	}
+
+	return sumCalibrationChunks(file, chunks, keep, workers)
+}
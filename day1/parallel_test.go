@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitChunks(t *testing.T) {
+	content := "1abc2\npqr3stu8vwx\na1b2c3d4e5f\ntreb7uchet"
+	input := strings.NewReader(content)
+
+	for chunkSize := int64(1); chunkSize <= int64(len(content))+1; chunkSize++ {
+		chunks, err := splitChunks(input, int64(len(content)), chunkSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Chunks must cover the whole input, each one starting a line
+		position := int64(0)
+		for _, c := range chunks {
+			if c.start != position {
+				t.Fatalf("For chunk size %d, chunk %v does not follow %d", chunkSize, c, position)
+			}
+			if c.start > 0 && content[c.start-1] != '\n' {
+				t.Fatalf("For chunk size %d, chunk %v does not start a line", chunkSize, c)
+			}
+			position = c.end
+		}
+		if position != int64(len(content)) {
+			t.Fatalf("For chunk size %d, chunks %v do not cover the input", chunkSize, chunks)
+		}
+	}
+}
+
+func TestSumCalibrationChunks(t *testing.T) {
+	lines := []string{"two1nine", "eightwothree", "abcone2threexyz", "xtwone3four", "4nineeightseven2", "zoneight234", "7pqrstsixteen"}
+	content := strings.Join(lines, "\n") + "\n"
+	input := strings.NewReader(content)
+
+	expected, err := solvePart2(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, chunkSize := range []int64{1, 7, 20, 1000} {
+		for _, workers := range []int{1, 3, 16} {
+			chunks, err := splitChunks(input, int64(len(content)), chunkSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sum, err := sumCalibrationChunks(input, chunks, keepFirstAndLast, workers)
+			if err != nil || sum != expected {
+				t.Errorf("For chunk size %d and %d workers, expected %d, got %d and error %v", chunkSize, workers, expected, sum, err)
+			}
+		}
+	}
+}
+
+func TestSumCalibrationChunksErrorLine(t *testing.T) {
+	lines := []string{"1abc2", "pqr3stu8vwx", "a1b2c3d4e5f", "no digits", "treb7uchet", "none either"}
+	content := strings.Join(lines, "\n")
+	input := strings.NewReader(content)
+
+	for _, chunkSize := range []int64{1, 12, 1000} {
+		chunks, err := splitChunks(input, int64(len(content)), chunkSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = sumCalibrationChunks(input, chunks, keepFirstAndLastDigits, 4)
+		if err == nil || !strings.HasPrefix(err.Error(), "line 4:") {
+			t.Errorf("For chunk size %d, expected an error on line 4, got %v", chunkSize, err)
+		}
+	}
+}
+
+func TestSumCalibrationFile(t *testing.T) {
+	var builder strings.Builder
+	expected := 0
+	for i := 0; i < 50000; i++ {
+		first, last := i%9+1, i%7+1
+		fmt.Fprintf(&builder, "ab%dcd%s%def\n", first, strings.Repeat("x", i%13), last)
+		expected += first*10 + last
+	}
+
+	path := filepath.Join(t.TempDir(), "calibration.txt")
+	if err := os.WriteFile(path, []byte(builder.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sum, err := sumCalibrationFile(path, keepFirstAndLastDigits, 4)
+	if err != nil || sum != expected {
+		t.Errorf("Expected %d, got %d and error %v", expected, sum, err)
+	}
+
+	if _, err := sumCalibrationFile("nonexistentfile.txt", keepFirstAndLastDigits, 4); err == nil {
+		t.Error("Expected an error for a non-existent file, but got none")
+	}
+}
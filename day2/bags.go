@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// namedBag is one of the bags a game may have been drawn from
+type namedBag struct {
+	name string
+	bag  cubeSet
+}
+
+// parseBags reads bags written <name>=<set> and separated by semicolons, e.g.
+// "default=12 red, 13 green, 14 blue; small=5 red, 5 green, 5 blue", a color
+// missing from a set having no cube in the bag
+func parseBags(spec string) ([]namedBag, error) {
+
+	var bags []namedBag
+	seen := make(map[string]bool)
+
+	re := regexp.MustCompile(`^\s*(\d+) (blue|red|green)\s*$`)
+	for _, bagString := range strings.Split(spec, ";") {
+		name, setString, found := strings.Cut(bagString, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, errors.New("invalid bag " + strconv.Quote(bagString) + ", expected <name>=<set>")
+		}
+		if seen[name] {
+			return nil, errors.New("bag " + name + " is given twice")
+		}
+		seen[name] = true
+
+		bag := namedBag{name: name}
+		for _, cubes := range strings.Split(setString, ",") {
+			match := re.FindStringSubmatch(cubes)
+			if match == nil {
+				return nil, errors.New("invalid cubes " + strconv.Quote(cubes) + " in bag " + name + ", expected <count> red, green or blue")
+			}
+			count, err := strconv.Atoi(match[1])
+			if err != nil {
+				return nil, errors.New("failed to convert count to integer: " + err.Error())
+			}
+			switch match[2] {
+			case "blue":
+				bag.bag.blue += count
+			case "red":
+				bag.bag.red += count
+			case "green":
+				bag.bag.green += count
+			}
+		}
+		bags = append(bags, bag)
+	}
+
+	return bags, nil
+}
+
+// bagClassification tells which bags a game is consistent with
+type bagClassification struct {
+	id         int
+	consistent []bool
+}
+
+func (classification bagClassification) count() int {
+	count := 0
+	for _, consistent := range classification.consistent {
+		if consistent {
+			count++
+		}
+	}
+	return count
+}
+
+// classifyGame checks a game against each bag under a draw rule
+func classifyGame(game string, bags []namedBag, rule string) (bagClassification, error) {
+
+	var classification bagClassification
+
+	if rule != ruleReplacement && rule != ruleNoReplacement {
+		return classification, errors.New("unknown draw rule " + rule + ", expected " + ruleReplacement + " or " + ruleNoReplacement)
+	}
+
+	gameSets, err := parseGameSets(game)
+	if err != nil {
+		return classification, err
+	}
+
+	classification.id, err = getGameID(game)
+	if err != nil {
+		return classification, err
+	}
+
+	for _, bag := range bags {
+		impossibleDraw := firstImpossibleDraw(gameSets, bag.bag, rule == ruleReplacement)
+		classification.consistent = append(classification.consistent, impossibleDraw == 0)
+	}
+
+	return classification, nil
+}
+
+// bagTotals are the answers of part 1 for each bag, and the games consistent with
+// no bag or with every bag
+type bagTotals struct {
+	bags    []namedBag
+	games   []bagClassification
+	idsSums []int
+	none    []int
+	all     []int
+}
+
+// classifyGames classifies every game against the bags and sums the IDs of the
+// games consistent with each bag
+func classifyGames(games []string, bags []namedBag, rule string) (bagTotals, error) {
+
+	totals := bagTotals{bags: bags, idsSums: make([]int, len(bags))}
+
+	for _, game := range games {
+		classification, err := classifyGame(game, bags, rule)
+		if err != nil {
+			return totals, err
+		}
+
+		for i, consistent := range classification.consistent {
+			if consistent {
+				totals.idsSums[i] += classification.id
+			}
+		}
+
+		switch classification.count() {
+		case 0:
+			totals.none = append(totals.none, classification.id)
+		case len(bags):
+			totals.all = append(totals.all, classification.id)
+		}
+
+		totals.games = append(totals.games, classification)
+	}
+
+	return totals, nil
+}
+
+// writeMatrix writes the game x bag matrix, marking with x the bags each game is
+// consistent with, followed by the IDs sum of each bag
+func (totals bagTotals) writeMatrix(output io.Writer) error {
+
+	writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+
+	header := []string{"game"}
+	for _, bag := range totals.bags {
+		header = append(header, bag.name)
+	}
+	fmt.Fprintln(writer, strings.Join(header, "\t")+"\t")
+
+	for _, classification := range totals.games {
+		row := []string{strconv.Itoa(classification.id)}
+		for _, consistent := range classification.consistent {
+			if consistent {
+				row = append(row, "x")
+			} else {
+				row = append(row, ".")
+			}
+		}
+		switch classification.count() {
+		case 0:
+			row = append(row, "no bag")
+		case len(totals.bags):
+			row = append(row, "every bag")
+		default:
+			row = append(row, "")
+		}
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+
+	sums := []string{"IDs sum"}
+	for _, sum := range totals.idsSums {
+		sums = append(sums, strconv.Itoa(sum))
+	}
+	fmt.Fprintln(writer, strings.Join(sums, "\t")+"\t")
+
+	return writer.Flush()
+}
+
+// joinIds lists game IDs, or none
+func joinIds(ids []int) string {
+	if len(ids) == 0 {
+		return "none"
+	}
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = strconv.Itoa(id)
+	}
+	return strings.Join(idStrings, ", ")
+}
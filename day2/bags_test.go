@@ -0,0 +1,125 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBags(t *testing.T) {
+	testCases := []struct {
+		spec     string
+		expected []namedBag
+		err      bool
+	}{
+		{"default=12 red, 13 green, 14 blue", []namedBag{{"default", cubeSet{red: 12, green: 13, blue: 14}}}, false},
+		{"a=1 red; b = 2 blue", []namedBag{{"a", cubeSet{red: 1}}, {"b", cubeSet{blue: 2}}}, false},
+		{"a=1 red; a=2 red", nil, true},
+		{"=1 red", nil, true},
+		{"a", nil, true},
+		{"a=1 yellow", nil, true},
+		{"a=red", nil, true},
+	}
+
+	for _, testCase := range testCases {
+		bags, err := parseBags(testCase.spec)
+		if (err != nil) != testCase.err || !reflect.DeepEqual(bags, testCase.expected) {
+			t.Errorf("For bags %q, expected %v and error %t, but got %v and %v", testCase.spec, testCase.expected, testCase.err, bags, err)
+		}
+	}
+}
+
+func TestClassifyGames(t *testing.T) {
+	bags, err := parseBags("default=12 red, 13 green, 14 blue; small=5 red, 5 green, 5 blue; big=20 red, 20 green, 20 blue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	games, err := readLinesFrom(strings.NewReader(exampleInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		rule       string
+		consistent [][]bool
+		idsSums    []int
+		none       []int
+		all        []int
+	}{
+		{
+			ruleReplacement,
+			[][]bool{{true, false, true}, {true, true, true}, {false, false, true}, {false, false, true}, {true, false, true}},
+			[]int{8, 2, 15},
+			nil,
+			[]int{2},
+		},
+		{
+			// the cubes of games 3 and 4 add up to more than 20 of a color
+			ruleNoReplacement,
+			[][]bool{{true, false, true}, {true, false, true}, {false, false, false}, {false, false, false}, {true, false, true}},
+			[]int{8, 0, 8},
+			[]int{3, 4},
+			nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		totals, err := classifyGames(games, bags, testCase.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, classification := range totals.games {
+			if classification.id != i+1 || !reflect.DeepEqual(classification.consistent, testCase.consistent[i]) {
+				t.Errorf("For game %d with rule %s, expected %v, but got %+v", i+1, testCase.rule, testCase.consistent[i], classification)
+			}
+		}
+		if !reflect.DeepEqual(totals.idsSums, testCase.idsSums) || !reflect.DeepEqual(totals.none, testCase.none) || !reflect.DeepEqual(totals.all, testCase.all) {
+			t.Errorf("For rule %s, expected sums %v, none %v and all %v, but got %v, %v and %v",
+				testCase.rule, testCase.idsSums, testCase.none, testCase.all, totals.idsSums, totals.none, totals.all)
+		}
+	}
+
+	if _, err := classifyGames(games, bags, "unknown"); err == nil {
+		t.Error("Expected an error for an unknown draw rule")
+	}
+}
+
+func TestWriteMatrix(t *testing.T) {
+	bags, err := parseBags("small=5 red, 5 green, 5 blue; big=20 red, 20 green, 20 blue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	totals, err := classifyGames([]string{"Game 1: 3 red", "Game 2: 8 red", "Game 3: 30 red"}, bags, ruleReplacement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var output strings.Builder
+	if err := totals.writeMatrix(&output); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"game     small  big",
+		"1        x      x    every bag",
+		"2        .      x",
+		"3        .      .    no bag",
+		"IDs sum  1      3",
+	}
+	lines := strings.Split(strings.TrimRight(output.String(), "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected matrix %q, but got %q", expected, lines)
+	}
+}
+
+func TestJoinIds(t *testing.T) {
+	if joined := joinIds(nil); joined != "none" {
+		t.Errorf("For no IDs, expected none, but got %s", joined)
+	}
+	if joined := joinIds([]int{3, 4}); joined != "3, 4" {
+		t.Errorf("For IDs 3 and 4, expected 3, 4, but got %s", joined)
+	}
+}
@@ -2,10 +2,17 @@ package main
 
 import (
 	"bufio"
+	"context"
+	_ "embed"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 )
@@ -22,21 +29,172 @@ var theBag = cubeSet{
 	green: 13,
 }
 
+//go:embed input_test.txt
+var exampleInput string
+
+// defaultInputPath returns the input.txt stored next to this source file, so the
+// solver finds its puzzle input whatever the current working directory is
+func defaultInputPath() string {
+	_, source, _, ok := runtime.Caller(0)
+	if ok {
+		path := filepath.Join(filepath.Dir(source), "input.txt")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return "./input.txt"
+}
+
 // read each line of file and add to a slice
 func readLines(filename string) ([]string, error) {
-	lines := make([]string, 0)
 	file, err := os.Open(filename)
 	if err != nil {
-		return lines, errors.New("fail to read lines from " + filename + " due to error " + err.Error())
+		return make([]string, 0), errors.New("fail to read lines from " + filename + " due to error " + err.Error())
 	}
 	defer file.Close()
-	scanner := bufio.NewScanner(file)
+	return readLinesFrom(file)
+}
+
+// read each line of a reader and add to a slice
+func readLinesFrom(reader io.Reader) ([]string, error) {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
 		lines = append(lines, scanner.Text())
 	}
+	if err := scanner.Err(); err != nil {
+		return lines, errors.New("fail to read lines due to error " + err.Error())
+	}
 	return lines, nil
 }
 
+// firstImpossibleDraw returns the number, from 1, of the first set of a game
+// drawing more cubes of a color than the bag holds, or 0 when every set fits.
+// Without replacement, the cubes revealed by the previous sets of the game are
+// not put back in the bag.
+func firstImpossibleDraw(gameSets []cubeSet, bag cubeSet, replacement bool) int {
+
+	var drawn cubeSet
+
+	for i, gameSet := range gameSets {
+
+		if replacement {
+			drawn = gameSet
+		} else {
+			drawn.blue += gameSet.blue
+			drawn.red += gameSet.red
+			drawn.green += gameSet.green
+		}
+
+		if drawn.blue > bag.blue || drawn.red > bag.red || drawn.green > bag.green {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// sumGameSets returns all the cubes revealed by a game, which is the minimal bag
+// when cubes are not put back in the bag
+func sumGameSets(gameSets []cubeSet) cubeSet {
+
+	var sumGameSet cubeSet
+
+	for _, gameSet := range gameSets {
+		sumGameSet.blue += gameSet.blue
+		sumGameSet.red += gameSet.red
+		sumGameSet.green += gameSet.green
+	}
+
+	return sumGameSet
+}
+
+// rules deciding if the cubes revealed by a set go back in the bag
+const (
+	ruleReplacement   = "replacement"
+	ruleNoReplacement = "no-replacement"
+)
+
+type gameReport struct {
+	id int
+	// first set making the game impossible with theBag, 0 when possible
+	impossibleDraw int
+	minimalBag     cubeSet
+}
+
+func (report gameReport) possible() bool {
+	return report.impossibleDraw == 0
+}
+
+func (report gameReport) power() int {
+	return report.minimalBag.blue * report.minimalBag.green * report.minimalBag.red
+}
+
+// evaluateGame checks a game against theBag under a draw rule, and computes the
+// minimal bag the game needs under that rule
+func evaluateGame(game string, rule string) (gameReport, error) {
+
+	var report gameReport
+
+	gameSets, err := parseGameSets(game)
+	if err != nil {
+		return report, err
+	}
+
+	report.id, err = getGameID(game)
+	if err != nil {
+		return report, err
+	}
+
+	switch rule {
+	case ruleReplacement:
+		report.impossibleDraw = firstImpossibleDraw(gameSets, theBag, true)
+		report.minimalBag = getMaxColorGameSet(gameSets)
+	case ruleNoReplacement:
+		report.impossibleDraw = firstImpossibleDraw(gameSets, theBag, false)
+		report.minimalBag = sumGameSets(gameSets)
+	default:
+		return report, errors.New("unknown draw rule " + rule + ", expected " + ruleReplacement + " or " + ruleNoReplacement)
+	}
+
+	return report, nil
+}
+
+func (report gameReport) String() string {
+	bag := fmt.Sprintf("minimal bag %d red, %d green, %d blue", report.minimalBag.red, report.minimalBag.green, report.minimalBag.blue)
+	if report.possible() {
+		return fmt.Sprintf("Game %d: possible, %s", report.id, bag)
+	}
+	return fmt.Sprintf("Game %d: impossible at draw %d, %s", report.id, report.impossibleDraw, bag)
+}
+
+// openInput opens the puzzle input, the example bundled with the solver, stdin when
+// inputPath is "-", or the inputPath file
+func openInput(inputPath string, example bool) (io.ReadCloser, error) {
+	if example {
+		return io.NopCloser(strings.NewReader(exampleInput)), nil
+	}
+	if inputPath == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return nil, errors.New("fail to read lines from " + inputPath + " due to error " + err.Error())
+	}
+	return file, nil
+}
+
+// loadInput returns the puzzle lines, read from the example bundled with the
+// solver, from stdin when inputPath is "-", or from the inputPath file
+func loadInput(inputPath string, example bool) ([]string, error) {
+	input, err := openInput(inputPath, example)
+	if err != nil {
+		return make([]string, 0), err
+	}
+	defer input.Close()
+	return readLinesFrom(input)
+}
+
 func getGameID(input string) (int, error) {
 	// Define a regular expression pattern to match the game ID
 	pattern := `Game (\d+):`
@@ -127,57 +285,106 @@ func getMaxColorGameSet(gameSets []cubeSet) cubeSet {
 }
 
 func isGameSetsPossible(gameSets []cubeSet) bool {
+	return firstImpossibleDraw(gameSets, theBag, true) == 0
+}
 
-	for _, game := range gameSets {
+func main() {
 
-		if game.blue > theBag.blue {
-			return false
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := runGenerate(os.Args[2:]); err != nil {
+			panic(err.Error())
 		}
+		return
+	}
 
-		if game.red > theBag.red {
-			return false
-		}
+	inputPath := flag.String("input", defaultInputPath(), "puzzle input file, or - to read stdin")
+	example := flag.Bool("example", false, "solve the example bundled with the solver instead of the input")
+	stream := flag.Bool("stream", false, "read games one at a time, printing the running totals after each one")
+	follow := flag.Bool("follow", false, "stream games, then wait for new games appended to the input until interrupted")
+	rule := flag.String("rule", ruleReplacement, "draw rule, "+ruleReplacement+" or "+ruleNoReplacement+" when revealed cubes are kept out of the bag")
+	details := flag.Bool("details", false, "print the first impossible draw and the minimal bag of each game")
+	bagsSpec := flag.String("bags", "", "named bags to classify the games against instead of solving the puzzle, e.g. \"default=12 red, 13 green, 14 blue; small=5 red, 5 green, 5 blue\"")
+	flag.Parse()
+
+	if *rule != ruleReplacement && *rule != ruleNoReplacement {
+		panic("unknown draw rule " + *rule + ", expected " + ruleReplacement + " or " + ruleNoReplacement)
+	}
 
-		if game.green > theBag.green {
-			return false
+	if *bagsSpec != "" {
+		if *stream || *follow {
+			panic("--bags cannot be used with --stream or --follow")
+		}
+		if err := runBags(*inputPath, *example, *bagsSpec, *rule); err != nil {
+			panic(err.Error())
 		}
+		return
 	}
 
-	return true
-}
+	if *stream || *follow {
+		input, err := openInput(*inputPath, *example)
+		if err != nil {
+			panic(err.Error())
+		}
+		defer input.Close()
 
-func main() {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
 
-	var possibleIdsSum int
-	var gameSetPowerSum int
+		_, err = streamGames(ctx, input, *rule, *follow, func(totals runningTotals, report gameReport) {
+			if *details {
+				fmt.Println(report)
+			}
+			fmt.Printf("game #%d - possible IDs sum: %d, power sets sum: %d\n", totals.games, totals.possibleIdsSum, totals.powerSum)
+		})
+		if err != nil {
+			panic(err.Error())
+		}
+		return
+	}
 
 	//read input file
-	games, err := readLines("./input.txt")
+	games, err := loadInput(*inputPath, *example)
 	if err != nil {
 		panic(err.Error())
 	}
 
+	totals := runningTotals{rule: *rule}
 	for _, game := range games {
-
-		gameSets, err := parseGameSets(game)
+		report, err := totals.add(game)
 		if err != nil {
 			panic(err.Error())
 		}
+		if *details {
+			fmt.Println(report)
+		}
+	}
 
-		maxGameSet := getMaxColorGameSet(gameSets)
-		gameSetPower := maxGameSet.blue * maxGameSet.green * maxGameSet.red
-		gameSetPowerSum += gameSetPower
+	fmt.Println("possible IDs sum: ", totals.possibleIdsSum)
+	fmt.Println("power sets sum: ", totals.powerSum)
+}
 
-		if isGameSetsPossible(gameSets) {
-			id, err := getGameID(game)
-			if err != nil {
-				panic(err.Error())
-			}
+// runBags prints which of the bags each game could have been drawn from
+func runBags(inputPath string, example bool, spec string, rule string) error {
 
-			possibleIdsSum += id
-		}
+	bags, err := parseBags(spec)
+	if err != nil {
+		return err
 	}
 
-	fmt.Println("possible IDs sum: ", possibleIdsSum)
-	fmt.Println("power sets sum: ", gameSetPowerSum)
+	games, err := loadInput(inputPath, example)
+	if err != nil {
+		return err
+	}
+
+	totals, err := classifyGames(games, bags, rule)
+	if err != nil {
+		return err
+	}
+
+	if err := totals.writeMatrix(os.Stdout); err != nil {
+		return err
+	}
+	fmt.Println("games consistent with no bag: ", joinIds(totals.none))
+	fmt.Println("games consistent with every bag: ", joinIds(totals.all))
+	return nil
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 )
@@ -40,6 +41,48 @@ func TestReadLines(t *testing.T) {
 	}
 }
 
+func TestLoadInput(t *testing.T) {
+	// Create a temporary file with sample content
+	tmpfile, err := os.CreateTemp("", "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name()) // clean up
+
+	if _, err := tmpfile.WriteString("Line 1\nLine 2"); err != nil {
+		t.Fatal(err)
+	}
+	tmpfile.Close()
+
+	// Test reading the input from a file path
+	lines, err := loadInput(tmpfile.Name(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lines, []string{"Line 1", "Line 2"}) {
+		t.Errorf("Expected file lines, but got %v", lines)
+	}
+
+	// Test reading the bundled example, which must ignore the input path
+	lines, err = loadInput("nonexistentfile.txt", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) == 0 {
+		t.Error("Expected the bundled example lines, but got none")
+	}
+}
+
+func TestDefaultInputPath(t *testing.T) {
+	path := defaultInputPath()
+	if filepath.Base(path) != "input.txt" {
+		t.Errorf("Expected a path to input.txt, but got %s", path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected default input %s to exist, but got %v", path, err)
+	}
+}
+
 func TestGetGameID(t *testing.T) {
 	testCases := []struct {
 		input       string
@@ -194,3 +237,145 @@ func TestIsGameSetsPossible(t *testing.T) {
 		}
 	}
 }
+
+// benchmarkLines returns the puzzle input used by the benchmarks
+func benchmarkLines(b *testing.B) []string {
+	lines, err := readLines(defaultInputPath())
+	if err != nil {
+		b.Skip("no puzzle input to benchmark: " + err.Error())
+	}
+	return lines
+}
+
+// benchmarkGames parses every game of the benchmark input
+func benchmarkGames(b *testing.B, lines []string) ([]int, [][]cubeSet) {
+	var ids []int
+	var games [][]cubeSet
+	for _, line := range lines {
+		id, err := getGameID(line)
+		if err != nil {
+			b.Fatal(err)
+		}
+		gameSets, err := parseGameSets(line)
+		if err != nil {
+			b.Fatal(err)
+		}
+		ids = append(ids, id)
+		games = append(games, gameSets)
+	}
+	return ids, games
+}
+
+func BenchmarkPart1(b *testing.B) {
+	lines := benchmarkLines(b)
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchmarkGames(b, lines)
+		}
+	})
+
+	ids, games := benchmarkGames(b, lines)
+	b.Run("solve", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			possibleIdsSum := 0
+			for gameNb, gameSets := range games {
+				if isGameSetsPossible(gameSets) {
+					possibleIdsSum += ids[gameNb]
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkPart2(b *testing.B) {
+	lines := benchmarkLines(b)
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchmarkGames(b, lines)
+		}
+	})
+
+	_, games := benchmarkGames(b, lines)
+	b.Run("solve", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			gameSetPowerSum := 0
+			for _, gameSets := range games {
+				maxGameSet := getMaxColorGameSet(gameSets)
+				gameSetPowerSum += maxGameSet.blue * maxGameSet.green * maxGameSet.red
+			}
+		}
+	})
+}
+
+func TestFirstImpossibleDraw(t *testing.T) {
+	bag := cubeSet{red: 12, blue: 14, green: 13}
+	gameSets := []cubeSet{
+		{red: 3, green: 1, blue: 6},
+		{red: 6, green: 3},
+		{red: 4, green: 3, blue: 5},
+		{red: 14, green: 3, blue: 15},
+	}
+
+	testCases := []struct {
+		gameSets    []cubeSet
+		replacement bool
+		expected    int
+	}{
+		{gameSets, true, 4},
+		{gameSets, false, 3},
+		{gameSets[:2], true, 0},
+		{gameSets[:2], false, 0},
+		{nil, false, 0},
+	}
+
+	for _, testCase := range testCases {
+		result := firstImpossibleDraw(testCase.gameSets, bag, testCase.replacement)
+		if result != testCase.expected {
+			t.Errorf("For sets %v and replacement %v, expected %d, but got %d", testCase.gameSets, testCase.replacement, testCase.expected, result)
+		}
+	}
+}
+
+func TestSumGameSets(t *testing.T) {
+	result := sumGameSets([]cubeSet{{3, 5, 2}, {1, 8, 4}, {7, 3, 6}})
+	expected := cubeSet{11, 16, 12}
+	if result != expected {
+		t.Errorf("Expected %v, but got %v", expected, result)
+	}
+}
+
+func TestEvaluateGame(t *testing.T) {
+	game := "Game 4: 1 green, 3 red, 6 blue; 3 green, 6 red; 3 green, 15 blue, 14 red"
+
+	testCases := []struct {
+		rule     string
+		expected gameReport
+	}{
+		{ruleReplacement, gameReport{id: 4, impossibleDraw: 3, minimalBag: cubeSet{red: 14, green: 3, blue: 15}}},
+		{ruleNoReplacement, gameReport{id: 4, impossibleDraw: 3, minimalBag: cubeSet{red: 23, green: 7, blue: 21}}},
+	}
+
+	for _, testCase := range testCases {
+		result, err := evaluateGame(game, testCase.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != testCase.expected {
+			t.Errorf("For rule %s, expected %v, but got %v", testCase.rule, testCase.expected, result)
+		}
+	}
+
+	// kept cubes make a game possible with replacement impossible
+	report, err := evaluateGame("Game 7: 8 red; 8 red", ruleNoReplacement)
+	if err != nil || report.possible() || report.impossibleDraw != 2 {
+		t.Errorf("Expected the game to be impossible at draw 2, got %v and error %v", report, err)
+	}
+
+	if _, err := evaluateGame(game, "magic"); err == nil {
+		t.Error("Expected an error for an unknown rule")
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func isASCIIDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// referenceParseGameSets is an oracle for parseGameSets scanning each set for the
+// digit runs followed by a space and a color
+func referenceParseGameSets(line string) ([]cubeSet, error) {
+	var sets []cubeSet
+
+	for _, setString := range strings.Split(line, ";") {
+		set := cubeSet{}
+
+		for i := 0; i < len(setString); i++ {
+			if !isASCIIDigit(setString[i]) || (i > 0 && isASCIIDigit(setString[i-1])) {
+				continue
+			}
+			end := i
+			for end < len(setString) && isASCIIDigit(setString[end]) {
+				end++
+			}
+
+			rest := setString[end:]
+			var color *int
+			var colorLength int
+			switch {
+			case strings.HasPrefix(rest, " blue"):
+				color, colorLength = &set.blue, len(" blue")
+			case strings.HasPrefix(rest, " red"):
+				color, colorLength = &set.red, len(" red")
+			case strings.HasPrefix(rest, " green"):
+				color, colorLength = &set.green, len(" green")
+			default:
+				continue
+			}
+
+			count, err := strconv.Atoi(setString[i:end])
+			if err != nil {
+				return nil, err
+			}
+			*color += count
+			i = end + colorLength - 1
+		}
+
+		sets = append(sets, set)
+	}
+
+	return sets, nil
+}
+
+// referenceGetGameID is an oracle for getGameID looking for the first "Game <digits>:"
+func referenceGetGameID(line string) (int, error) {
+	for i := strings.Index(line, "Game "); i >= 0; {
+		start := i + len("Game ")
+		end := start
+		for end < len(line) && isASCIIDigit(line[end]) {
+			end++
+		}
+		if end > start && end < len(line) && line[end] == ':' {
+			id, err := strconv.Atoi(line[start:end])
+			if err != nil {
+				return 0, err
+			}
+			return id, nil
+		}
+
+		next := strings.Index(line[i+1:], "Game ")
+		if next < 0 {
+			break
+		}
+		i += 1 + next
+	}
+	return 0, errors.New("no game ID found in the string")
+}
+
+func FuzzParseGameSets(f *testing.F) {
+	for _, seed := range []string{
+		"Game 1: 3 blue, 4 red; 1 red, 2 green, 6 blue; 2 green",
+		"Game 3: 8 green, 6 blue, 20 red; 5 blue, 4 red, 13 green; 5 green, 1 red",
+		"Game 4: 1 green, 3 red, 6 blue; 3 green, 6 red; 3 green, 15 blue, 14 red",
+		"1 blue 2 blue;;3 redgreen",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		expected, expectedErr := referenceParseGameSets(line)
+		actual, err := parseGameSets(line)
+
+		if (err != nil) != (expectedErr != nil) {
+			t.Fatalf("For line %q, expected error %v, but got %v", line, expectedErr, err)
+		}
+		if err == nil && !equalCubeSets(actual, expected) {
+			t.Errorf("For line %q, expected %v, but got %v", line, expected, actual)
+		}
+	})
+}
+
+func FuzzGetGameID(f *testing.F) {
+	for _, seed := range []string{"Game 1: 3 blue", "Game 42: 5 red,", "No game here", "Game abc: 2 green,", "Game : 1 yellow,", "Game Game 7:"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		expected, expectedErr := referenceGetGameID(line)
+		actual, err := getGameID(line)
+
+		if (err != nil) != (expectedErr != nil) {
+			t.Fatalf("For line %q, expected error %v, but got %v", line, expectedErr, err)
+		}
+		if actual != expected {
+			t.Errorf("For line %q, expected %d, but got %d", line, expected, actual)
+		}
+	})
+}
+
+func equalCubeSets(a, b []cubeSet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// colorCount is a number of cubes of a color
+type colorCount struct {
+	color string
+	count int
+}
+
+type generatorConfig struct {
+	seed     int64
+	bag      []colorCount
+	games    int
+	minDraws int
+	maxDraws int
+	// probability of a draw revealing more cubes of a color than the bag holds
+	impossible float64
+}
+
+// expectedGame is the expected evaluation of a generated game against its bag
+type expectedGame struct {
+	id       int
+	possible bool
+	power    int
+}
+
+// parseBag reads a bag written like a set of a game, e.g. "12 red, 13 green, 14 blue",
+// in which any color name is accepted
+func parseBag(spec string) ([]colorCount, error) {
+	var bag []colorCount
+	seen := make(map[string]bool)
+
+	re := regexp.MustCompile(`^\s*(\d+) (\w+)\s*$`)
+	for _, part := range strings.Split(spec, ",") {
+		match := re.FindStringSubmatch(part)
+		if match == nil {
+			return nil, errors.New("invalid bag color " + strconv.Quote(part) + ", expected <count> <color>")
+		}
+		count, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, errors.New("failed to convert count to integer: " + err.Error())
+		}
+		if count < 1 {
+			return nil, errors.New("bag color " + match[2] + " needs at least one cube")
+		}
+		if seen[match[2]] {
+			return nil, errors.New("bag color " + match[2] + " is given twice")
+		}
+		seen[match[2]] = true
+		bag = append(bag, colorCount{match[2], count})
+	}
+
+	return bag, nil
+}
+
+func (conf generatorConfig) validate() error {
+	if len(conf.bag) == 0 {
+		return errors.New("the bag needs at least one color")
+	}
+	if conf.games < 0 {
+		return fmt.Errorf("invalid games %d", conf.games)
+	}
+	if conf.minDraws < 1 || conf.maxDraws < conf.minDraws {
+		return fmt.Errorf("invalid draws range %d to %d", conf.minDraws, conf.maxDraws)
+	}
+	if conf.impossible < 0 || conf.impossible > 1 {
+		return fmt.Errorf("invalid impossible draw probability %v, expected 0 to 1", conf.impossible)
+	}
+	return nil
+}
+
+// generateDraw returns the cubes revealed by a draw, one or more colors of the bag
+// in random order, and whether the draw exceeds the bag
+func generateDraw(rnd *rand.Rand, conf generatorConfig) ([]colorCount, bool) {
+
+	var draw []colorCount
+	for _, i := range rnd.Perm(len(conf.bag)) {
+		if len(draw) == 0 || rnd.Intn(2) == 0 {
+			draw = append(draw, colorCount{conf.bag[i].color, 1 + rnd.Intn(conf.bag[i].count)})
+		}
+	}
+
+	if rnd.Float64() >= conf.impossible {
+		return draw, false
+	}
+
+	over := rnd.Intn(len(draw))
+	for _, c := range conf.bag {
+		if c.color == draw[over].color {
+			draw[over].count = c.count + 1 + rnd.Intn(c.count)
+		}
+	}
+	return draw, true
+}
+
+// generateGames builds a game log from a seed, with the expected evaluation of each
+// game: possible when no draw exceeds the bag, and the power of its minimal bag
+// over the colors of the bag. The day2 solver only reads red, green and blue cubes,
+// so it finds the same answers for a bag of exactly these colors, given with --bags
+// when it is not theBag.
+func generateGames(conf generatorConfig) ([]string, []expectedGame, error) {
+
+	if err := conf.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	rnd := rand.New(rand.NewSource(conf.seed))
+
+	var lines []string
+	var expected []expectedGame
+
+	for id := 1; id <= conf.games; id++ {
+		game := expectedGame{id: id, possible: true, power: 1}
+		maxColors := make(map[string]int)
+
+		var sets []string
+		draws := conf.minDraws + rnd.Intn(conf.maxDraws-conf.minDraws+1)
+		for i := 0; i < draws; i++ {
+			draw, over := generateDraw(rnd, conf)
+			if over {
+				game.possible = false
+			}
+
+			var cubes []string
+			for _, c := range draw {
+				cubes = append(cubes, strconv.Itoa(c.count)+" "+c.color)
+				if c.count > maxColors[c.color] {
+					maxColors[c.color] = c.count
+				}
+			}
+			sets = append(sets, strings.Join(cubes, ", "))
+		}
+
+		// colors never revealed need no cube, making the power 0
+		for _, c := range conf.bag {
+			game.power *= maxColors[c.color]
+		}
+
+		lines = append(lines, fmt.Sprintf("Game %d: %s", id, strings.Join(sets, "; ")))
+		expected = append(expected, game)
+	}
+
+	return lines, expected, nil
+}
+
+// writeGenerated writes the games to path, and the expected evaluation of each
+// game followed by the totals to path.expected
+func writeGenerated(path string, lines []string, expected []expectedGame) error {
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		return errors.New("fail to write generated games to " + path + " due to error " + err.Error())
+	}
+
+	file, err := os.Create(path + ".expected")
+	if err != nil {
+		return errors.New("fail to write expected games due to error " + err.Error())
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	fmt.Fprintln(writer, "# game\tpossible\tpower")
+	possibleIdsSum, powerSum := 0, 0
+	for _, game := range expected {
+		fmt.Fprintf(writer, "%d\t%t\t%d\n", game.id, game.possible, game.power)
+		if game.possible {
+			possibleIdsSum += game.id
+		}
+		powerSum += game.power
+	}
+	fmt.Fprintf(writer, "possible IDs sum\t%d\n", possibleIdsSum)
+	fmt.Fprintf(writer, "power sets sum\t%d\n", powerSum)
+
+	if err := writer.Flush(); err != nil {
+		return errors.New("fail to write expected games due to error " + err.Error())
+	}
+	return file.Close()
+}
+
+func runGenerate(args []string) error {
+
+	conf := generatorConfig{}
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	out := flags.String("out", "generated.txt", "file receiving the games, expected evaluations going to <out>.expected")
+	bag := flags.String("bag", "12 red, 13 green, 14 blue", "bag the games are drawn from, in any colors, though the solver only reads red, green and blue cubes")
+	flags.Int64Var(&conf.seed, "seed", 1, "seed of the generator, the same seed giving the same games")
+	flags.IntVar(&conf.games, "games", 100, "number of games")
+	flags.IntVar(&conf.minDraws, "min-draws", 1, "minimal number of draws per game")
+	flags.IntVar(&conf.maxDraws, "max-draws", 6, "maximal number of draws per game")
+	flags.Float64Var(&conf.impossible, "impossible", 0.05, "probability of a draw revealing more cubes than the bag holds")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var err error
+	conf.bag, err = parseBag(*bag)
+	if err != nil {
+		return err
+	}
+
+	lines, expected, err := generateGames(conf)
+	if err != nil {
+		return err
+	}
+	if err := writeGenerated(*out, lines, expected); err != nil {
+		return err
+	}
+
+	fmt.Println("generated", len(lines), "games to", *out, "and", *out+".expected")
+	return nil
+}
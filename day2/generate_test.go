@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// gameMaxima returns the most cubes of each color revealed by a game, whatever the
+// color, unlike parseGameSets which only reads red, green and blue cubes
+func gameMaxima(t *testing.T, line string) map[string]int {
+	maxima := make(map[string]int)
+	for _, match := range regexp.MustCompile(`(\d+) (\w+)`).FindAllStringSubmatch(line, -1) {
+		count, err := strconv.Atoi(match[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count > maxima[match[2]] {
+			maxima[match[2]] = count
+		}
+	}
+	return maxima
+}
+
+func TestGenerateGames(t *testing.T) {
+	testCases := []struct {
+		name string
+		conf generatorConfig
+	}{
+		{"puzzle bag", generatorConfig{seed: 42, bag: []colorCount{{"red", 12}, {"green", 13}, {"blue", 14}}, games: 2000, minDraws: 1, maxDraws: 6, impossible: 0.1}},
+		{"small bag", generatorConfig{seed: 7, bag: []colorCount{{"blue", 1}, {"red", 2}, {"green", 3}}, games: 500, minDraws: 2, maxDraws: 4, impossible: 0.2}},
+		{"other colors", generatorConfig{seed: 3, bag: []colorCount{{"yellow", 3}, {"purple", 5}}, games: 500, minDraws: 1, maxDraws: 3, impossible: 0.1}},
+	}
+
+	for _, testCase := range testCases {
+		lines, expected, err := generateGames(testCase.conf)
+		if err != nil {
+			t.Fatalf("For %s, expected no error, got %v", testCase.name, err)
+		}
+		if len(lines) != testCase.conf.games || len(expected) != testCase.conf.games {
+			t.Fatalf("For %s, expected %d games, got %d lines and %d evaluations", testCase.name, testCase.conf.games, len(lines), len(expected))
+		}
+
+		// The evaluation recorded for each game holds for the colors of the bag
+		impossible := 0
+		for i, line := range lines {
+			id, err := getGameID(line)
+			if err != nil || id != expected[i].id {
+				t.Errorf("For game %s, expected ID %d, got %d and error %v", line, expected[i].id, id, err)
+			}
+			maxima := gameMaxima(t, line)
+			possible, power := true, 1
+			for _, c := range testCase.conf.bag {
+				possible = possible && maxima[c.color] <= c.count
+				power *= maxima[c.color]
+			}
+			if len(maxima) > len(testCase.conf.bag) {
+				t.Errorf("For game %s, expected only colors of the bag %v", line, testCase.conf.bag)
+			}
+			if possible != expected[i].possible || power != expected[i].power {
+				t.Errorf("For game %s, expected possible %t and power %d, got %+v", line, possible, power, expected[i])
+			}
+			if !possible {
+				impossible++
+			}
+		}
+		if impossible == 0 || impossible == testCase.conf.games {
+			t.Errorf("For %s, expected both possible and impossible games, got %d impossible games", testCase.name, impossible)
+		}
+
+		// The same seed gives the same games
+		again, _, err := generateGames(testCase.conf)
+		if err != nil || !reflect.DeepEqual(lines, again) {
+			t.Errorf("For %s, expected the same games for the same seed, got error %v", testCase.name, err)
+		}
+	}
+}
+
+func TestGenerateGamesSolved(t *testing.T) {
+	// the solver reads red, green and blue cubes, checking games against other bags
+	// with --bags
+	for _, spec := range []string{"12 red, 13 green, 14 blue", "4 red, 2 green, 6 blue"} {
+		bag, err := parseBag(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines, expected, err := generateGames(generatorConfig{seed: 11, bag: bag, games: 300, minDraws: 1, maxDraws: 5, impossible: 0.1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "generated.txt")
+		if err := writeGenerated(path, lines, expected); err != nil {
+			t.Fatal(err)
+		}
+
+		games, err := loadInput(path, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bags, err := parseBags("generated=" + spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		classified, err := classifyGames(games, bags, ruleReplacement)
+		if err != nil {
+			t.Fatal(err)
+		}
+		totals := runningTotals{rule: ruleReplacement}
+		for _, game := range games {
+			if _, err := totals.add(game); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		expectedLines, err := readLines(path + ".expected")
+		if err != nil {
+			t.Fatal(err)
+		}
+		solved := []string{fmt.Sprintf("possible IDs sum\t%d", classified.idsSums[0]), fmt.Sprintf("power sets sum\t%d", totals.powerSum)}
+		if !reflect.DeepEqual(solved, expectedLines[len(expectedLines)-2:]) {
+			t.Errorf("For bag %q, expected %q, but got %q", spec, expectedLines[len(expectedLines)-2:], solved)
+		}
+	}
+}
+
+func TestParseBag(t *testing.T) {
+	testCases := []struct {
+		spec     string
+		expected []colorCount
+		err      bool
+	}{
+		{"12 red, 13 green, 14 blue", []colorCount{{"red", 12}, {"green", 13}, {"blue", 14}}, false},
+		{"5 yellow", []colorCount{{"yellow", 5}}, false},
+		{"5 yellow, 2 yellow", nil, true},
+		{"0 red", nil, true},
+		{"red", nil, true},
+		{"", nil, true},
+	}
+
+	for _, testCase := range testCases {
+		bag, err := parseBag(testCase.spec)
+		if (err != nil) != testCase.err || !reflect.DeepEqual(bag, testCase.expected) {
+			t.Errorf("For bag %q, expected %v and error %t, but got %v and %v", testCase.spec, testCase.expected, testCase.err, bag, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// how often a followed file is checked for new games
+const followPollInterval = 200 * time.Millisecond
+
+// runningTotals are the answers for the games read so far, under a draw rule
+type runningTotals struct {
+	rule           string
+	games          int
+	possibleIdsSum int
+	powerSum       int
+}
+
+// add evaluates a game and adds it to the totals
+func (totals *runningTotals) add(game string) (gameReport, error) {
+
+	report, err := evaluateGame(game, totals.rule)
+	if err != nil {
+		return report, err
+	}
+
+	totals.powerSum += report.power()
+	if report.possible() {
+		totals.possibleIdsSum += report.id
+	}
+
+	totals.games++
+	return report, nil
+}
+
+// streamGames reads the games one line at a time and calls emit with the running
+// totals and the report of each game, holding a single game in memory. With follow, reaching the
+// end of the input waits for more games to be appended, like tail -f, until ctx is
+// done; a line is only evaluated once its newline is written.
+func streamGames(ctx context.Context, reader io.Reader, rule string, follow bool, emit func(runningTotals, gameReport)) (runningTotals, error) {
+
+	totals := runningTotals{rule: rule}
+	var pending strings.Builder
+	buffered := bufio.NewReader(reader)
+
+	for {
+		chunk, err := buffered.ReadString('\n')
+		pending.WriteString(chunk)
+
+		if err == io.EOF {
+			if !follow {
+				// last game without newline
+				if game := strings.TrimSpace(pending.String()); game != "" {
+					report, err := totals.add(game)
+					if err != nil {
+						return totals, err
+					}
+					emit(totals, report)
+				}
+				return totals, nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return totals, nil
+			case <-time.After(followPollInterval):
+			}
+			continue
+		}
+		if err != nil {
+			return totals, errors.New("fail to read games due to error " + err.Error())
+		}
+
+		game := strings.TrimSpace(pending.String())
+		pending.Reset()
+		if game == "" {
+			continue
+		}
+		report, err := totals.add(game)
+		if err != nil {
+			return totals, err
+		}
+		emit(totals, report)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamGames(t *testing.T) {
+	// blank lines are skipped and the last game has no newline
+	input := strings.TrimSpace(exampleInput) + "\n\n"
+	input = strings.Replace(input, "\nGame 3", "\n\nGame 3", 1)
+	input = strings.TrimSuffix(input, "\n\n")
+
+	var emitted []runningTotals
+	totals, err := streamGames(context.Background(), strings.NewReader(input), ruleReplacement, false, func(totals runningTotals, report gameReport) {
+		emitted = append(emitted, totals)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := runningTotals{rule: ruleReplacement, games: 5, possibleIdsSum: 8, powerSum: 2286}
+	if totals != expected {
+		t.Errorf("Expected %v, got %v", expected, totals)
+	}
+	if len(emitted) != 5 || emitted[0] != (runningTotals{rule: ruleReplacement, games: 1, possibleIdsSum: 1, powerSum: 48}) {
+		t.Errorf("Expected the totals after each of the 5 games, got %v", emitted)
+	}
+
+	_, err = streamGames(context.Background(), strings.NewReader("Game 1: 99999999999999999999 red\n"), ruleReplacement, false, func(runningTotals, gameReport) {})
+	if err == nil {
+		t.Error("Expected an error for an invalid game")
+	}
+}
+
+func TestStreamGamesFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.log")
+	if err := os.WriteFile(path, []byte("Game 1: 3 blue, 4 red; 1 red, 2 green, 6 blue; 2 green\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	emitted := make(chan runningTotals, 10)
+	done := make(chan runningTotals)
+	go func() {
+		totals, err := streamGames(ctx, file, ruleReplacement, true, func(totals runningTotals, report gameReport) {
+			emitted <- totals
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		done <- totals
+	}()
+
+	if totals := <-emitted; totals.games != 1 {
+		t.Fatalf("Expected the first game, got %v", totals)
+	}
+
+	// append a game in two writes, it is only evaluated once complete
+	appendFile := func(content string) {
+		log, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer log.Close()
+		if _, err := log.WriteString(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	appendFile("Game 2: 1 blue, 2 green; 3 green")
+	select {
+	case totals := <-emitted:
+		t.Fatalf("Expected no totals for an incomplete game, got %v", totals)
+	case <-time.After(3 * followPollInterval):
+	}
+	appendFile(", 4 blue, 1 red; 1 green, 1 blue\n")
+
+	select {
+	case totals := <-emitted:
+		expected := runningTotals{rule: ruleReplacement, games: 2, possibleIdsSum: 3, powerSum: 60}
+		if totals != expected {
+			t.Errorf("Expected %v, got %v", expected, totals)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the appended game to be streamed")
+	}
+
+	cancel()
+	if totals := <-done; totals.games != 2 {
+		t.Errorf("Expected 2 games when stopping, got %v", totals)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// schematicCell is a position in the schematic
+type schematicCell struct {
+	line   int
+	indice int
+}
+
+// assembly is a maximal group of engine numbers and symbols whose characters touch,
+// diagonals included
+type assembly struct {
+	// bounding box
+	firstLine   int
+	lastLine    int
+	firstIndice int
+	lastIndice  int
+	symbols     []string
+	numbers     []int
+	sum         int
+	// product of the numbers, 1 for an assembly of symbols only
+	product *big.Int
+}
+
+func (found assembly) hasSymbol(symbol string) bool {
+	for _, s := range found.symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func (found assembly) String() string {
+	return fmt.Sprintf("lines %d-%d, columns %d-%d, symbols [%s], numbers %v, sum %d, product %s",
+		found.firstLine+1, found.lastLine+1, found.firstIndice+1, found.lastIndice+1,
+		strings.Join(found.symbols, " "), found.numbers, found.sum, found.product)
+}
+
+// assemblyItem is a number or a symbol of the schematic
+type assemblyItem struct {
+	cell   schematicCell
+	last   int
+	symbol string
+	number int
+}
+
+// findAssemblies groups the numbers and symbols of a schematic into assemblies with
+// a union-find over their cells, in reading order of their first item. A number or a
+// symbol touching nothing is an assembly of its own.
+func findAssemblies(symbolsMatrix map[int]map[int]string, engineNumbers []engineNumber) []assembly {
+
+	var items []assemblyItem
+	for _, number := range engineNumbers {
+		items = append(items, assemblyItem{
+			cell:   schematicCell{number.line, number.startIndice},
+			last:   number.endIndice,
+			number: number.number,
+		})
+	}
+	for line, indiceMap := range symbolsMatrix {
+		for indice, symbol := range indiceMap {
+			items = append(items, assemblyItem{cell: schematicCell{line, indice}, last: indice, symbol: symbol})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].cell.line != items[j].cell.line {
+			return items[i].cell.line < items[j].cell.line
+		}
+		return items[i].cell.indice < items[j].cell.indice
+	})
+
+	// item covering each cell
+	cells := make(map[schematicCell]int)
+	for i, item := range items {
+		for indice := item.cell.indice; indice <= item.last; indice++ {
+			cells[schematicCell{item.cell.line, indice}] = i
+		}
+	}
+
+	parents := make([]int, len(items))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+
+	for cell, i := range cells {
+		for line := cell.line - 1; line <= cell.line+1; line++ {
+			for indice := cell.indice - 1; indice <= cell.indice+1; indice++ {
+				if j, ok := cells[schematicCell{line, indice}]; ok {
+					// the root with the first item in reading order is kept
+					rootI, rootJ := find(i), find(j)
+					if rootI < rootJ {
+						parents[rootJ] = rootI
+					} else if rootJ < rootI {
+						parents[rootI] = rootJ
+					}
+				}
+			}
+		}
+	}
+
+	var assemblies []assembly
+	byRoot := make(map[int]int)
+	for i, item := range items {
+		root := find(i)
+		index, ok := byRoot[root]
+		if !ok {
+			index = len(assemblies)
+			byRoot[root] = index
+			assemblies = append(assemblies, assembly{
+				firstLine:   item.cell.line,
+				lastLine:    item.cell.line,
+				firstIndice: item.cell.indice,
+				lastIndice:  item.last,
+				product:     big.NewInt(1),
+			})
+		}
+
+		found := &assemblies[index]
+		if item.cell.line > found.lastLine {
+			found.lastLine = item.cell.line
+		}
+		if item.cell.indice < found.firstIndice {
+			found.firstIndice = item.cell.indice
+		}
+		if item.last > found.lastIndice {
+			found.lastIndice = item.last
+		}
+
+		if item.symbol != "" {
+			found.symbols = append(found.symbols, item.symbol)
+			continue
+		}
+		found.numbers = append(found.numbers, item.number)
+		found.sum += item.number
+		found.product.Mul(found.product, big.NewInt(int64(item.number)))
+	}
+
+	return assemblies
+}
+
+// filterAssemblies keeps the assemblies holding the symbol
+func filterAssemblies(assemblies []assembly, symbol string) []assembly {
+	var result []assembly
+	for _, found := range assemblies {
+		if found.hasSymbol(symbol) {
+			result = append(result, found)
+		}
+	}
+	return result
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func assembliesOf(t *testing.T, lines []string) []assembly {
+	numbers, err := findEngineNumbers(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return findAssemblies(findEngineSymbols(lines), numbers)
+}
+
+func TestFindAssemblies(t *testing.T) {
+	lines, err := readLinesFrom(strings.NewReader(exampleInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assemblies := assembliesOf(t, lines)
+
+	if len(assemblies) != 8 {
+		t.Fatalf("Expected 8 assemblies, but got %d: %v", len(assemblies), assemblies)
+	}
+	expected := assembly{
+		firstLine: 0, lastLine: 2, firstIndice: 0, lastIndice: 3,
+		symbols: []string{"*"}, numbers: []int{467, 35}, sum: 502, product: big.NewInt(16345),
+	}
+	if !reflect.DeepEqual(assemblies[0], expected) {
+		t.Errorf("Expected first assembly %v, but got %v", expected, assemblies[0])
+	}
+	if assemblies[1].symbols != nil || !reflect.DeepEqual(assemblies[1].numbers, []int{114}) {
+		t.Errorf("Expected 114 alone in the second assembly, but got %v", assemblies[1])
+	}
+}
+
+func TestFindAssembliesChains(t *testing.T) {
+	testCases := []struct {
+		lines    []string
+		expected [][]int
+	}{
+		// numbers and symbols chaining through diagonals
+		{[]string{"12.....", "..*....", "...34..", ".....#.", "......5"}, [][]int{{12, 34, 5}}},
+		// numbers touching each other without a symbol
+		{[]string{"12..", "..34"}, [][]int{{12, 34}}},
+		{[]string{"12.34", "....."}, [][]int{{12}, {34}}},
+		// a symbol joining two branches
+		{[]string{"1.2", ".*.", "3.4"}, [][]int{{1, 2, 3, 4}}},
+		{[]string{"#..", "...", "..5"}, [][]int{nil, {5}}},
+	}
+
+	for _, testCase := range testCases {
+		var numbers [][]int
+		for _, found := range assembliesOf(t, testCase.lines) {
+			numbers = append(numbers, found.numbers)
+		}
+		if !reflect.DeepEqual(numbers, testCase.expected) {
+			t.Errorf("For schematic %q, expected assemblies %v, but got %v", testCase.lines, testCase.expected, numbers)
+		}
+	}
+}
+
+func TestFindAssembliesBoundingBox(t *testing.T) {
+	assemblies := assembliesOf(t, []string{"....9", "...#.", "77*..", "....."})
+	if len(assemblies) != 1 {
+		t.Fatalf("Expected a single assembly, but got %v", assemblies)
+	}
+	found := assemblies[0]
+	if found.firstLine != 0 || found.lastLine != 2 || found.firstIndice != 0 || found.lastIndice != 4 {
+		t.Errorf("Expected bounding box lines 0-2 and columns 0-4, but got %v", found)
+	}
+	if found.sum != 86 || found.product.Cmp(big.NewInt(693)) != 0 || !reflect.DeepEqual(found.symbols, []string{"#", "*"}) {
+		t.Errorf("Expected sum 86, product 693 and symbols # and *, but got %v", found)
+	}
+}
+
+func TestFindAssembliesLargeProduct(t *testing.T) {
+	// the product of the numbers would overflow an int
+	line := strings.Repeat("999*", 10)
+	assemblies := assembliesOf(t, []string{line})
+	expected := new(big.Int).Exp(big.NewInt(999), big.NewInt(10), nil)
+	if len(assemblies) != 1 || assemblies[0].product.Cmp(expected) != 0 {
+		t.Errorf("Expected a single assembly with product %s, but got %v", expected, assemblies)
+	}
+}
+
+func TestFilterAssemblies(t *testing.T) {
+	assemblies := assembliesOf(t, []string{"1*..2#", "......", "3*#..4"})
+
+	var sums []int
+	for _, found := range filterAssemblies(assemblies, "#") {
+		sums = append(sums, found.sum)
+	}
+	if !reflect.DeepEqual(sums, []int{2, 3}) {
+		t.Errorf("Expected the assemblies of 2 and 3, but got sums %v", sums)
+	}
+	if filtered := filterAssemblies(assemblies, "$"); filtered != nil {
+		t.Errorf("Expected no assembly holding $, but got %v", filtered)
+	}
+}
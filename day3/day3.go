@@ -2,10 +2,16 @@ package main
 
 import (
 	"bufio"
+	_ "embed"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strconv"
+	"strings"
 	"unicode"
 )
 
@@ -23,21 +29,72 @@ type gear struct {
 	ratio   int
 }
 
+//go:embed input_test.txt
+var exampleInput string
+
+// defaultInputPath returns the input.txt stored next to this source file, so the
+// solver finds its puzzle input whatever the current working directory is
+func defaultInputPath() string {
+	_, source, _, ok := runtime.Caller(0)
+	if ok {
+		path := filepath.Join(filepath.Dir(source), "input.txt")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return "./input.txt"
+}
+
 // read each line of file and add to a slice
 func readLines(filename string) ([]string, error) {
-	lines := make([]string, 0)
 	file, err := os.Open(filename)
 	if err != nil {
-		return lines, errors.New("fail to read lines from " + filename + " due to error " + err.Error())
+		return make([]string, 0), errors.New("fail to read lines from " + filename + " due to error " + err.Error())
 	}
 	defer file.Close()
-	scanner := bufio.NewScanner(file)
+	return readLinesFrom(file)
+}
+
+// read each line of a reader and add to a slice
+func readLinesFrom(reader io.Reader) ([]string, error) {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
 		lines = append(lines, scanner.Text())
 	}
+	if err := scanner.Err(); err != nil {
+		return lines, errors.New("fail to read lines due to error " + err.Error())
+	}
 	return lines, nil
 }
 
+// openInput opens the puzzle input, the example bundled with the solver, stdin when
+// inputPath is "-", or the inputPath file
+func openInput(inputPath string, example bool) (io.ReadCloser, error) {
+	if example {
+		return io.NopCloser(strings.NewReader(exampleInput)), nil
+	}
+	if inputPath == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return nil, errors.New("fail to read lines from " + inputPath + " due to error " + err.Error())
+	}
+	return file, nil
+}
+
+// loadInput returns the puzzle lines, read from the example bundled with the
+// solver, from stdin when inputPath is "-", or from the inputPath file
+func loadInput(inputPath string, example bool) ([]string, error) {
+	input, err := openInput(inputPath, example)
+	if err != nil {
+		return make([]string, 0), err
+	}
+	defer input.Close()
+	return readLinesFrom(input)
+}
+
 // findEngineSymbols takes a slice of strings (lines) and creates a map of maps
 // to store non-alphanumeric characters found in the lines.
 // The outer map uses line numbers as keys, and the inner map uses character indexes
@@ -123,30 +180,7 @@ func isNumberInInterval(nb int, interval []int) bool {
 }
 
 func isPartNumber(eNbr engineNumber, symbolsMatrix map[int]map[int]string) bool {
-
-	//check lines
-	possibleSymbolLines := []int{eNbr.line - 1, eNbr.line, eNbr.line + 1}
-	var effectiveSymbolsLines = []int{}
-	for _, line := range possibleSymbolLines {
-		if _, ok := symbolsMatrix[line]; ok {
-			effectiveSymbolsLines = append(effectiveSymbolsLines, line)
-		}
-	}
-	if len(effectiveSymbolsLines) == 0 {
-		return false
-	}
-
-	//check indices
-	interval := []int{eNbr.startIndice - 1, eNbr.endIndice + 1}
-	for _, line := range effectiveSymbolsLines {
-		for indice := range symbolsMatrix[line] {
-			if isNumberInInterval(indice, interval) {
-				return true
-			}
-		}
-	}
-
-	return false
+	return isPartNumberWithin(eNbr, symbolsMatrix, defaultNeighbourhood)
 }
 
 func getEnginPartNumbers(lines []string, symbols map[int]map[int]string, numbers []engineNumber) ([]int, error) {
@@ -201,12 +235,8 @@ func findEngineNumbersInInterval(numbers []engineNumber, possibleLines []int, po
 
 		if isIntInArray(number.line, possibleLines) {
 
-			if isNumberInInterval(number.startIndice, possiblesIndiceInterval) {
-				result = append(result, number.number)
-				continue
-			}
-
-			if isNumberInInterval(number.endIndice, possiblesIndiceInterval) {
+			// the number may also span the whole interval, neither end being inside
+			if number.startIndice <= possiblesIndiceInterval[1] && number.endIndice >= possiblesIndiceInterval[0] {
 				result = append(result, number.number)
 			}
 		}
@@ -215,51 +245,108 @@ func findEngineNumbersInInterval(numbers []engineNumber, possibleLines []int, po
 }
 
 func getGears(symbolsMatrix map[int]map[int]string, engineNumbers []engineNumber) []gear {
+	return getGearsWithin(symbolsMatrix, engineNumbers, defaultNeighbourhood)
+}
 
-	var gears []gear
+func main() {
 
-	// Iterate through the outer map
-	for symbolLine, indiceMap := range symbolsMatrix {
-		possibleLines := []int{symbolLine - 1, symbolLine, symbolLine + 1}
-		// Iterate through the inner map
-		for indice, symbol := range indiceMap {
-			if symbol != "*" {
-				continue
-			}
-			possiblesIndiceInterval := []int{indice - 1, indice + 1}
-			allNumbersInInterval := findEngineNumbersInInterval(engineNumbers, possibleLines, possiblesIndiceInterval)
-			if len(allNumbersInInterval) == 2 {
-				gears = append(gears, gear{
-					numbers: allNumbersInInterval,
-					ratio:   allNumbersInInterval[0] * allNumbersInInterval[1],
-				})
-			}
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := runGenerate(os.Args[2:]); err != nil {
+			panic(err.Error())
 		}
+		return
 	}
 
-	return gears
-}
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		if err := runDiff(os.Args[2:], os.Stdout); err != nil {
+			panic(err.Error())
+		}
+		return
+	}
 
-func main() {
+	inputPath := flag.String("input", defaultInputPath(), "puzzle input file, or - to read stdin")
+	example := flag.Bool("example", false, "solve the example bundled with the solver instead of the input")
+	stream := flag.Bool("stream", false, "scan the schematic three rows at a time instead of loading it whole")
+	details := flag.Bool("details", false, "with --stream, print each part number and gear as its rows are scanned")
+	assemblies := flag.Bool("assemblies", false, "list the groups of numbers and symbols touching each other")
+	symbol := flag.String("symbol", "", "with --assemblies, only list the assemblies holding this symbol")
+	kind := flag.String("neighbourhood", neighbourhoodDiagonal, "cells touching a number or a gear, "+neighbourhoodOrthogonal+" for the orthogonal ones or "+neighbourhoodDiagonal+" for diagonals too")
+	radius := flag.Int("radius", 1, "distance up to which cells touch")
+	wrap := flag.Bool("wrap", false, "make each edge of the schematic touch the opposite edge")
+	signed := flag.Bool("signed", false, "read a - or + directly before digits as the sign of the number instead of a symbol")
+	flag.Parse()
+
+	if *stream {
+		if *kind != neighbourhoodDiagonal || *radius != 1 || *wrap {
+			panic("--stream only supports the default neighbourhood")
+		}
+		if *signed {
+			panic("--stream does not support --signed")
+		}
+
+		input, err := openInput(*inputPath, *example)
+		if err != nil {
+			panic(err.Error())
+		}
+		defer input.Close()
+
+		var emitPart func(engineNumber)
+		var emitGear func(int, gear)
+		if *details {
+			emitPart = func(number engineNumber) {
+				fmt.Printf("line %d: part number %d\n", number.line+1, number.number)
+			}
+			emitGear = func(lineNb int, found gear) {
+				fmt.Printf("line %d: gear %d * %d = %d\n", lineNb+1, found.numbers[0], found.numbers[1], found.ratio)
+			}
+		}
+
+		totals, err := scanSchematic(input, emitPart, emitGear)
+		if err != nil {
+			panic(err.Error())
+		}
+		fmt.Println("Part 1 - sum of part numbers: ", totals.partNumbersSum)
+		fmt.Println("Part 2 - sum of gears ratio: ", totals.gearRatiosSum)
+		return
+	}
 
 	//read input file
-	lines, err := readLines("./input.txt")
+	lines, err := loadInput(*inputPath, *example)
 	if err != nil {
 		panic(err.Error())
 	}
 
-	symbols := findEngineSymbols(lines)
-	numbers, err := findEngineNumbers(lines)
+	var symbols map[int]map[int]string
+	var numbers []engineNumber
+	if *signed {
+		symbols = findSignedEngineSymbols(lines)
+		numbers, err = findSignedEngineNumbers(lines)
+	} else {
+		symbols = findEngineSymbols(lines)
+		numbers, err = findEngineNumbers(lines)
+	}
 	if err != nil {
 		panic(err.Error())
 	}
 
-	partNumbers, err := getEnginPartNumbers(lines, symbols, numbers)
+	if *assemblies {
+		found := findAssemblies(symbols, numbers)
+		if *symbol != "" {
+			found = filterAssemblies(found, *symbol)
+		}
+		for i, group := range found {
+			fmt.Printf("assembly %d: %s\n", i+1, group)
+		}
+		return
+	}
+
+	hood, err := newNeighbourhood(*kind, *radius, *wrap, lines)
 	if err != nil {
 		panic(err.Error())
 	}
 
-	gears := getGears(symbols, numbers)
+	partNumbers := getEnginPartNumbersWithin(symbols, numbers, hood)
+	gears := getGearsWithin(symbols, numbers, hood)
 	var sumGearRatio int
 	for _, gear := range gears {
 		sumGearRatio += gear.ratio
@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 )
@@ -40,6 +41,48 @@ func TestReadLines(t *testing.T) {
 	}
 }
 
+func TestLoadInput(t *testing.T) {
+	// Create a temporary file with sample content
+	tmpfile, err := os.CreateTemp("", "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name()) // clean up
+
+	if _, err := tmpfile.WriteString("Line 1\nLine 2"); err != nil {
+		t.Fatal(err)
+	}
+	tmpfile.Close()
+
+	// Test reading the input from a file path
+	lines, err := loadInput(tmpfile.Name(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lines, []string{"Line 1", "Line 2"}) {
+		t.Errorf("Expected file lines, but got %v", lines)
+	}
+
+	// Test reading the bundled example, which must ignore the input path
+	lines, err = loadInput("nonexistentfile.txt", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) == 0 {
+		t.Error("Expected the bundled example lines, but got none")
+	}
+}
+
+func TestDefaultInputPath(t *testing.T) {
+	path := defaultInputPath()
+	if filepath.Base(path) != "input.txt" {
+		t.Errorf("Expected a path to input.txt, but got %s", path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected default input %s to exist, but got %v", path, err)
+	}
+}
+
 func TestFindEngineSymbols(t *testing.T) {
 	// Example input lines
 	lines := []string{
@@ -348,6 +391,19 @@ func TestGetGears(t *testing.T) {
 				{numbers: []int{123, 456}, ratio: 123 * 456},
 			},
 		},
+		{
+			// found by FuzzFindEngineNumbers: a number spanning the whole gear window
+			map[int]map[int]string{
+				0: {2: "*"},
+			},
+			[]engineNumber{
+				{numberStr: "01", number: 1, startIndice: 0, endIndice: 1, line: 0},
+				{numberStr: "02323", number: 2323, startIndice: 0, endIndice: 4, line: 1},
+			},
+			[]gear{
+				{numbers: []int{1, 2323}, ratio: 2323},
+			},
+		},
 	}
 
 	// Iterate through test cases
@@ -360,3 +416,64 @@ func TestGetGears(t *testing.T) {
 		}
 	}
 }
+
+// benchmarkLines returns the puzzle input used by the benchmarks
+func benchmarkLines(b *testing.B) []string {
+	lines, err := readLines(defaultInputPath())
+	if err != nil {
+		b.Skip("no puzzle input to benchmark: " + err.Error())
+	}
+	return lines
+}
+
+// benchmarkSchematic parses the symbols and numbers of the benchmark input
+func benchmarkSchematic(b *testing.B, lines []string) (map[int]map[int]string, []engineNumber) {
+	numbers, err := findEngineNumbers(lines)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return findEngineSymbols(lines), numbers
+}
+
+func BenchmarkPart1(b *testing.B) {
+	lines := benchmarkLines(b)
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchmarkSchematic(b, lines)
+		}
+	})
+
+	symbols, numbers := benchmarkSchematic(b, lines)
+	b.Run("solve", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			partNumbers, err := getEnginPartNumbers(lines, symbols, numbers)
+			if err != nil {
+				b.Fatal(err)
+			}
+			sumInts(partNumbers)
+		}
+	})
+}
+
+func BenchmarkPart2(b *testing.B) {
+	lines := benchmarkLines(b)
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchmarkSchematic(b, lines)
+		}
+	})
+
+	symbols, numbers := benchmarkSchematic(b, lines)
+	b.Run("solve", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var sumGearRatio int
+			for _, gear := range getGears(symbols, numbers) {
+				sumGearRatio += gear.ratio
+			}
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// partChange is an engine number gaining or losing part status between two
+// revisions of a schematic
+type partChange struct {
+	number engineNumber
+	gained bool
+	// the number is in both revisions, only its surroundings changed
+	kept bool
+}
+
+func (change partChange) String() string {
+	position := fmt.Sprintf("line %d, columns %d-%d: %s", change.number.line+1, change.number.startIndice+1, change.number.endIndice+1, change.number.numberStr)
+	switch {
+	case change.gained && change.kept:
+		return position + " gained part status"
+	case change.gained:
+		return position + " added as a part number"
+	case change.kept:
+		return position + " lost part status"
+	default:
+		return position + " removed, it was a part number"
+	}
+}
+
+// gearChange is a gear appearing, disappearing or changing ratio between two
+// revisions of a schematic, before or after being nil when there is no gear
+type gearChange struct {
+	cell   schematicCell
+	before *gear
+	after  *gear
+}
+
+func (change gearChange) String() string {
+	position := fmt.Sprintf("line %d, column %d: ", change.cell.line+1, change.cell.indice+1)
+	switch {
+	case change.before == nil:
+		return position + fmt.Sprintf("gear appeared, %d * %d = %d", change.after.numbers[0], change.after.numbers[1], change.after.ratio)
+	case change.after == nil:
+		return position + fmt.Sprintf("gear disappeared, it was %d * %d = %d", change.before.numbers[0], change.before.numbers[1], change.before.ratio)
+	default:
+		return position + fmt.Sprintf("gear ratio changed from %d * %d = %d to %d * %d = %d",
+			change.before.numbers[0], change.before.numbers[1], change.before.ratio,
+			change.after.numbers[0], change.after.numbers[1], change.after.ratio)
+	}
+}
+
+// schematicDiff explains the change of both answers between two revisions
+type schematicDiff struct {
+	parts             []partChange
+	gears             []gearChange
+	partNumbersBefore int
+	partNumbersAfter  int
+	gearRatiosBefore  int
+	gearRatiosAfter   int
+}
+
+// solvedSchematic holds the part numbers and gears of a revision, numbers being
+// aligned on their position and digits
+type solvedSchematic struct {
+	numbers map[engineNumber]bool
+	gears   map[schematicCell]gear
+}
+
+func solveSchematic(lines []string) (solvedSchematic, error) {
+
+	solved := solvedSchematic{numbers: make(map[engineNumber]bool)}
+
+	symbols := findEngineSymbols(lines)
+	numbers, err := findEngineNumbers(lines)
+	if err != nil {
+		return solved, err
+	}
+
+	for _, number := range numbers {
+		solved.numbers[number] = isPartNumber(number, symbols)
+	}
+	solved.gears = locateGearsWithin(symbols, numbers, defaultNeighbourhood)
+
+	return solved, nil
+}
+
+// diffSchematics aligns two revisions of a schematic cell by cell
+func diffSchematics(before []string, after []string) (schematicDiff, error) {
+
+	var diff schematicDiff
+
+	old, err := solveSchematic(before)
+	if err != nil {
+		return diff, err
+	}
+	revised, err := solveSchematic(after)
+	if err != nil {
+		return diff, err
+	}
+
+	for number, isPart := range old.numbers {
+		if isPart {
+			diff.partNumbersBefore += number.number
+		}
+		revisedIsPart, kept := revised.numbers[number]
+		if isPart && !revisedIsPart {
+			diff.parts = append(diff.parts, partChange{number: number, gained: false, kept: kept})
+		}
+	}
+	for number, isPart := range revised.numbers {
+		if isPart {
+			diff.partNumbersAfter += number.number
+		}
+		oldIsPart, kept := old.numbers[number]
+		if isPart && !oldIsPart {
+			diff.parts = append(diff.parts, partChange{number: number, gained: true, kept: kept})
+		}
+	}
+	sort.Slice(diff.parts, func(i, j int) bool {
+		a, b := diff.parts[i].number, diff.parts[j].number
+		if a.line != b.line {
+			return a.line < b.line
+		}
+		if a.startIndice != b.startIndice {
+			return a.startIndice < b.startIndice
+		}
+		// a removed number comes before the number replacing it
+		return !diff.parts[i].gained && diff.parts[j].gained
+	})
+
+	for cell, oldGear := range old.gears {
+		diff.gearRatiosBefore += oldGear.ratio
+		oldGear := oldGear
+		revisedGear, ok := revised.gears[cell]
+		if !ok {
+			diff.gears = append(diff.gears, gearChange{cell: cell, before: &oldGear})
+		} else if revisedGear.ratio != oldGear.ratio {
+			diff.gears = append(diff.gears, gearChange{cell: cell, before: &oldGear, after: &revisedGear})
+		}
+	}
+	for cell, revisedGear := range revised.gears {
+		diff.gearRatiosAfter += revisedGear.ratio
+		revisedGear := revisedGear
+		if _, ok := old.gears[cell]; !ok {
+			diff.gears = append(diff.gears, gearChange{cell: cell, after: &revisedGear})
+		}
+	}
+	sort.Slice(diff.gears, func(i, j int) bool {
+		a, b := diff.gears[i].cell, diff.gears[j].cell
+		if a.line != b.line {
+			return a.line < b.line
+		}
+		return a.indice < b.indice
+	})
+
+	return diff, nil
+}
+
+func (diff schematicDiff) write(output io.Writer) {
+	fmt.Fprintln(output, "part numbers:")
+	for _, change := range diff.parts {
+		fmt.Fprintln(output, "  "+change.String())
+	}
+	fmt.Fprintln(output, "gears:")
+	for _, change := range diff.gears {
+		fmt.Fprintln(output, "  "+change.String())
+	}
+	fmt.Fprintf(output, "Part 1 - sum of part numbers: %d -> %d (%+d)\n",
+		diff.partNumbersBefore, diff.partNumbersAfter, diff.partNumbersAfter-diff.partNumbersBefore)
+	fmt.Fprintf(output, "Part 2 - sum of gears ratio: %d -> %d (%+d)\n",
+		diff.gearRatiosBefore, diff.gearRatiosAfter, diff.gearRatiosAfter-diff.gearRatiosBefore)
+}
+
+func runDiff(args []string, output io.Writer) error {
+
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: day3 diff <before> <after>")
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("diff expects two schematics")
+	}
+
+	before, err := readLines(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	after, err := readLines(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	diff, err := diffSchematics(before, after)
+	if err != nil {
+		return err
+	}
+	diff.write(output)
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffSchematics(t *testing.T) {
+	before, err := readLinesFrom(strings.NewReader(exampleInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after := make([]string, len(before))
+	copy(after, before)
+	// 114 gains part status next to a new symbol
+	after[0] = "467.*114.."
+	// the first gear becomes another symbol
+	after[1] = "...+......"
+	// 617 loses its symbol
+	after[4] = "617......."
+	// 592 is replaced by 593
+	after[6] = "..593....."
+	// the second gear changes ratio
+	after[7] = "......756."
+
+	diff, err := diffSchematics(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parts []string
+	for _, change := range diff.parts {
+		parts = append(parts, change.String())
+	}
+	expectedParts := []string{
+		"line 1, columns 6-8: 114 gained part status",
+		"line 5, columns 1-3: 617 lost part status",
+		"line 7, columns 3-5: 592 removed, it was a part number",
+		"line 7, columns 3-5: 593 added as a part number",
+		"line 8, columns 7-9: 755 removed, it was a part number",
+		"line 8, columns 7-9: 756 added as a part number",
+	}
+	if strings.Join(parts, "\n") != strings.Join(expectedParts, "\n") {
+		t.Errorf("Expected part changes %q, but got %q", expectedParts, parts)
+	}
+
+	var gears []string
+	for _, change := range diff.gears {
+		gears = append(gears, change.String())
+	}
+	expectedGears := []string{
+		"line 2, column 4: gear disappeared, it was 467 * 35 = 16345",
+		"line 9, column 6: gear ratio changed from 755 * 598 = 451490 to 756 * 598 = 452088",
+	}
+	if strings.Join(gears, "\n") != strings.Join(expectedGears, "\n") {
+		t.Errorf("Expected gear changes %q, but got %q", expectedGears, gears)
+	}
+
+	if diff.partNumbersBefore != 4361 || diff.partNumbersAfter != 4361+114-617+1+1 {
+		t.Errorf("Expected part numbers sum 4361 then %d, but got %+v", 4361+114-617+1+1, diff)
+	}
+	if diff.gearRatiosBefore != 467835 || diff.gearRatiosAfter != 452088 {
+		t.Errorf("Expected gear ratios sum 467835 then 452088, but got %+v", diff)
+	}
+}
+
+func TestDiffSchematicsAppearingGear(t *testing.T) {
+	diff, err := diffSchematics([]string{"12.3"}, []string{"12*3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.gears) != 1 || diff.gears[0].String() != "line 1, column 3: gear appeared, 12 * 3 = 36" {
+		t.Errorf("Expected a gear to appear, but got %v", diff.gears)
+	}
+	if len(diff.parts) != 2 || !diff.parts[0].gained || !diff.parts[0].kept {
+		t.Errorf("Expected 12 and 3 to gain part status, but got %v", diff.parts)
+	}
+
+	// an identical schematic has no change
+	diff, err = diffSchematics([]string{"12*3"}, []string{"12*3"})
+	if err != nil || diff.parts != nil || diff.gears != nil {
+		t.Errorf("Expected no change, but got %+v and error %v", diff, err)
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.txt")
+	after := filepath.Join(dir, "after.txt")
+	if err := os.WriteFile(before, []byte("12.3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(after, []byte("12*3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var output strings.Builder
+	if err := runDiff([]string{before, after}, &output); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(output.String(), "Part 1 - sum of part numbers: 0 -> 15 (+15)\nPart 2 - sum of gears ratio: 0 -> 36 (+36)\n") {
+		t.Errorf("Expected the net effect on both answers, but got %q", output.String())
+	}
+
+	if err := runDiff([]string{before}, &output); err == nil {
+		t.Error("Expected an error for a single schematic")
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+// referenceFindEngineSymbols is an oracle for findEngineSymbols decoding each rune
+// of the lines by hand
+func referenceFindEngineSymbols(lines []string) map[int]map[int]string {
+	symbols := make(map[int]map[int]string)
+	for lineNb, line := range lines {
+		for i := 0; i < len(line); {
+			char, width := utf8.DecodeRuneInString(line[i:])
+			if !unicode.IsNumber(char) && char != '.' {
+				if symbols[lineNb] == nil {
+					symbols[lineNb] = make(map[int]string)
+				}
+				symbols[lineNb][i] = string(char)
+			}
+			i += width
+		}
+	}
+	return symbols
+}
+
+// referenceFindEngineNumbers is an oracle for findEngineNumbers collecting the
+// maximal runs of digits of each line
+func referenceFindEngineNumbers(lines []string) ([]engineNumber, error) {
+	numbers := []engineNumber{}
+	for lineNb, line := range lines {
+		start, last := -1, -1
+		flush := func() error {
+			if start < 0 {
+				return nil
+			}
+			_, width := utf8.DecodeRuneInString(line[last:])
+			numberStr := line[start : last+width]
+			nb, err := strconv.Atoi(numberStr)
+			if err != nil {
+				return err
+			}
+			numbers = append(numbers, engineNumber{numberStr: numberStr, number: nb, startIndice: start, endIndice: last, line: lineNb})
+			start = -1
+			return nil
+		}
+
+		for i := 0; i < len(line); {
+			char, width := utf8.DecodeRuneInString(line[i:])
+			if unicode.IsDigit(char) {
+				if start < 0 {
+					start = i
+				}
+				last = i
+			} else if err := flush(); err != nil {
+				return nil, err
+			}
+			i += width
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	return numbers, nil
+}
+
+// referencePartNumbers is a brute-force oracle for getEnginPartNumbers, looking for a
+// symbol at every position around each number
+func referencePartNumbers(symbols map[int]map[int]string, numbers []engineNumber) []int {
+	var partNumbers []int
+	for _, nb := range numbers {
+		isPart := false
+		for line := nb.line - 1; line <= nb.line+1; line++ {
+			for indice := nb.startIndice - 1; indice <= nb.endIndice+1; indice++ {
+				if _, ok := symbols[line][indice]; ok {
+					isPart = true
+				}
+			}
+		}
+		if isPart {
+			partNumbers = append(partNumbers, nb.number)
+		}
+	}
+	return partNumbers
+}
+
+// referenceGearRatios is a brute-force oracle for getGears, returning the sorted
+// ratios of the "*" symbols touching exactly two numbers
+func referenceGearRatios(symbols map[int]map[int]string, numbers []engineNumber) []int {
+	var ratios []int
+	for line, indices := range symbols {
+		for indice, symbol := range indices {
+			if symbol != "*" {
+				continue
+			}
+			var adjacent []int
+			for _, nb := range numbers {
+				if nb.line >= line-1 && nb.line <= line+1 && nb.startIndice <= indice+1 && nb.endIndice >= indice-1 {
+					adjacent = append(adjacent, nb.number)
+				}
+			}
+			if len(adjacent) == 2 {
+				ratios = append(ratios, adjacent[0]*adjacent[1])
+			}
+		}
+	}
+	sort.Ints(ratios)
+	return ratios
+}
+
+// gearRatios returns the sorted ratios of gears
+func gearRatios(gears []gear) []int {
+	var ratios []int
+	for _, g := range gears {
+		ratios = append(ratios, g.ratio)
+	}
+	sort.Ints(ratios)
+	return ratios
+}
+
+var schematicSeeds = []string{
+	"467..114..\n...*......\n..35..633.\n......#...\n617*......\n.....+.58.\n..592.....\n......755.\n...$.*....\n.664.598..",
+	".......@...\n........982\n.370.......\n...*.......",
+	"12\n*",
+	"-42.7-\n",
+	"",
+}
+
+func FuzzFindEngineSymbols(f *testing.F) {
+	for _, seed := range schematicSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, schematic string) {
+		lines := strings.Split(schematic, "\n")
+		expected := referenceFindEngineSymbols(lines)
+		if actual := findEngineSymbols(lines); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("For schematic %q, expected %v, but got %v", schematic, expected, actual)
+		}
+	})
+}
+
+func FuzzFindEngineNumbers(f *testing.F) {
+	for _, seed := range schematicSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, schematic string) {
+		lines := strings.Split(schematic, "\n")
+		expected, expectedErr := referenceFindEngineNumbers(lines)
+		actual, err := findEngineNumbers(lines)
+
+		if (err != nil) != (expectedErr != nil) {
+			t.Fatalf("For schematic %q, expected error %v, but got %v", schematic, expectedErr, err)
+		}
+		if err != nil {
+			return
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("For schematic %q, expected %v, but got %v", schematic, expected, actual)
+		}
+
+		symbols := findEngineSymbols(lines)
+		expectedParts := referencePartNumbers(symbols, actual)
+		parts, err := getEnginPartNumbers(lines, symbols, actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(parts, expectedParts) {
+			t.Errorf("For schematic %q, expected part numbers %v, but got %v", schematic, expectedParts, parts)
+		}
+
+		expectedRatios := referenceGearRatios(symbols, actual)
+		if ratios := gearRatios(getGears(symbols, actual)); !reflect.DeepEqual(ratios, expectedRatios) {
+			t.Errorf("For schematic %q, expected gear ratios %v, but got %v", schematic, expectedRatios, ratios)
+		}
+	})
+}
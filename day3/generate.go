@@ -0,0 +1,327 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// symbols of the puzzle input other than *
+const defaultSymbols = "#+$@/=%&-"
+
+type generatorConfig struct {
+	seed   int64
+	width  int
+	height int
+	// probability of a free cell starting a number, or holding a symbol
+	numberDensity float64
+	symbolDensity float64
+	// probability of a symbol being a *, a gear when it touches two numbers
+	gearDensity float64
+	symbols     string
+	// times each edge case is planted
+	edgeCases int
+}
+
+// expectedTotals are the answers of a generated schematic
+type expectedTotals struct {
+	partNumbersSum int
+	gearRatiosSum  int
+}
+
+// where an edge case is planted
+const (
+	anchorAnywhere = iota
+	anchorLeft
+	anchorRight
+)
+
+// edgeCase is a pattern planted in the schematics, # standing for a symbol, * for a
+// gear and digits for a random number of as many digits
+type edgeCase struct {
+	template []string
+	anchor   int
+}
+
+var edgeCases = []edgeCase{
+	// numbers touching the border, and ending the line
+	{[]string{"11#"}, anchorLeft},
+	{[]string{"#11"}, anchorRight},
+	// a number spanning the whole window of a gear, neither of its ends being in it
+	{[]string{"11...", "..*..", "11111"}, anchorAnywhere},
+	// numbers sharing a symbol, or a gear
+	{[]string{"11.11", "..#.."}, anchorAnywhere},
+	{[]string{"11.11", "..*.."}, anchorAnywhere},
+}
+
+func (conf generatorConfig) validate() error {
+	if conf.width < 5 || conf.height < 3 {
+		return fmt.Errorf("invalid size %dx%d, the edge cases need at least 5x3", conf.width, conf.height)
+	}
+	for _, density := range []float64{conf.numberDensity, conf.symbolDensity, conf.gearDensity} {
+		if density < 0 || density > 1 {
+			return fmt.Errorf("invalid density %v, expected 0 to 1", density)
+		}
+	}
+	if conf.symbols == "" {
+		return errors.New("at least one symbol is needed")
+	}
+	for _, char := range conf.symbols {
+		if char > '~' || char <= ' ' || char == '.' || (char >= '0' && char <= '9') {
+			return fmt.Errorf("invalid symbol %q, expected a printable ASCII character other than a digit or .", char)
+		}
+	}
+	if conf.edgeCases < 0 {
+		return fmt.Errorf("invalid edge cases %d", conf.edgeCases)
+	}
+	return nil
+}
+
+// schematicGrid is a schematic being generated, with the cells reserved for the
+// edge cases and their surroundings
+type schematicGrid struct {
+	cells    [][]byte
+	reserved [][]bool
+}
+
+func (grid schematicGrid) free(line int, indice int) bool {
+	return line >= 0 && line < len(grid.cells) && indice >= 0 && indice < len(grid.cells[line]) &&
+		grid.cells[line][indice] == '.' && !grid.reserved[line][indice]
+}
+
+// fits tells if a number of length digits can start at a position without merging
+// with another number
+func (grid schematicGrid) fits(line int, indice int, length int) bool {
+	for i := indice; i < indice+length; i++ {
+		if !grid.free(line, i) {
+			return false
+		}
+	}
+	for _, i := range []int{indice - 1, indice + length} {
+		if i >= 0 && i < len(grid.cells[line]) && grid.cells[line][i] >= '0' && grid.cells[line][i] <= '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// plant writes an edge case at a position, reserving the cells around it when it fits
+func (grid schematicGrid) plant(rnd *rand.Rand, template []string, line int, indice int, symbols string) bool {
+
+	for l := line - 1; l <= line+len(template); l++ {
+		for i := indice - 1; i <= indice+len(template[0]); i++ {
+			if l >= 0 && l < len(grid.cells) && i >= 0 && i < len(grid.cells[l]) && grid.reserved[l][i] {
+				return false
+			}
+		}
+	}
+	if line < 0 || line+len(template) > len(grid.cells) || indice < 0 || indice+len(template[0]) > len(grid.cells[0]) {
+		return false
+	}
+
+	for l, row := range template {
+		for i := 0; i < len(row); i++ {
+			char := row[i]
+			switch {
+			case char == '#':
+				char = symbols[rnd.Intn(len(symbols))]
+			case char >= '0' && char <= '9':
+				// numbers never start with 0
+				char = byte('0' + rnd.Intn(10))
+				if i == 0 || row[i-1] < '0' || row[i-1] > '9' {
+					char = byte('1' + rnd.Intn(9))
+				}
+			}
+			grid.cells[line+l][indice+i] = char
+		}
+	}
+	for l := line - 1; l <= line+len(template); l++ {
+		for i := indice - 1; i <= indice+len(template[0]); i++ {
+			if l >= 0 && l < len(grid.cells) && i >= 0 && i < len(grid.cells[l]) {
+				grid.reserved[l][i] = true
+			}
+		}
+	}
+	return true
+}
+
+// generateSchematic builds a schematic from a seed, with its expected answers
+func generateSchematic(conf generatorConfig) ([]string, expectedTotals, error) {
+
+	if err := conf.validate(); err != nil {
+		return nil, expectedTotals{}, err
+	}
+
+	rnd := rand.New(rand.NewSource(conf.seed))
+
+	grid := schematicGrid{}
+	for line := 0; line < conf.height; line++ {
+		grid.cells = append(grid.cells, []byte(strings.Repeat(".", conf.width)))
+		grid.reserved = append(grid.reserved, make([]bool, conf.width))
+	}
+
+	// the edge cases go first, with a few tries to find room
+	for _, edge := range edgeCases {
+		height, width := len(edge.template), len(edge.template[0])
+		for n := 0; n < conf.edgeCases; n++ {
+			for try := 0; try < 20; try++ {
+				line := rnd.Intn(conf.height - height + 1)
+				indice := rnd.Intn(conf.width - width + 1)
+				switch edge.anchor {
+				case anchorLeft:
+					indice = 0
+				case anchorRight:
+					indice = conf.width - width
+				}
+				// the first and the last lines are borders too
+				if edge.anchor != anchorAnywhere && rnd.Intn(2) == 0 {
+					line = (conf.height - 1) * rnd.Intn(2)
+				}
+				if grid.plant(rnd, edge.template, line, indice, conf.symbols) {
+					break
+				}
+			}
+		}
+	}
+
+	// random numbers and symbols fill the free cells, numbers kept apart
+	for line := 0; line < conf.height; line++ {
+		for indice := 0; indice < conf.width; indice++ {
+			if !grid.free(line, indice) {
+				continue
+			}
+			draw := rnd.Float64()
+			switch {
+			case draw < conf.numberDensity:
+				number := strconv.Itoa(1 + rnd.Intn(999))
+				if grid.fits(line, indice, len(number)) {
+					copy(grid.cells[line][indice:], number)
+					indice += len(number)
+				}
+			case draw < conf.numberDensity+conf.symbolDensity:
+				if rnd.Float64() < conf.gearDensity {
+					grid.cells[line][indice] = '*'
+				} else {
+					grid.cells[line][indice] = conf.symbols[rnd.Intn(len(conf.symbols))]
+				}
+			}
+		}
+	}
+
+	lines := make([]string, conf.height)
+	for line, cells := range grid.cells {
+		lines[line] = string(cells)
+	}
+	return lines, solveGrid(grid.cells), nil
+}
+
+// solveGrid computes the answers of a schematic cell by cell, independently from the
+// solver, looking at every cell around each number and each *
+func solveGrid(cells [][]byte) expectedTotals {
+
+	var totals expectedTotals
+
+	isDigit := func(line int, indice int) bool {
+		return line >= 0 && line < len(cells) && indice >= 0 && indice < len(cells[line]) &&
+			cells[line][indice] >= '0' && cells[line][indice] <= '9'
+	}
+
+	type located struct {
+		line, start, end, value int
+	}
+	var numbers []located
+	for line := range cells {
+		for indice := 0; indice < len(cells[line]); indice++ {
+			if !isDigit(line, indice) {
+				continue
+			}
+			number := located{line: line, start: indice}
+			for ; isDigit(line, indice); indice++ {
+				number.value = number.value*10 + int(cells[line][indice]-'0')
+			}
+			number.end = indice - 1
+			numbers = append(numbers, number)
+		}
+	}
+
+	for _, number := range numbers {
+		isPart := false
+		for line := number.line - 1; line <= number.line+1; line++ {
+			for indice := number.start - 1; indice <= number.end+1; indice++ {
+				if line >= 0 && line < len(cells) && indice >= 0 && indice < len(cells[line]) &&
+					cells[line][indice] != '.' && !isDigit(line, indice) {
+					isPart = true
+				}
+			}
+		}
+		if isPart {
+			totals.partNumbersSum += number.value
+		}
+	}
+
+	for line := range cells {
+		for indice, char := range cells[line] {
+			if char != '*' {
+				continue
+			}
+			var adjacent []int
+			for _, number := range numbers {
+				if number.line >= line-1 && number.line <= line+1 && number.start <= indice+1 && number.end >= indice-1 {
+					adjacent = append(adjacent, number.value)
+				}
+			}
+			if len(adjacent) == 2 {
+				totals.gearRatiosSum += adjacent[0] * adjacent[1]
+			}
+		}
+	}
+
+	return totals
+}
+
+// writeGenerated writes the schematic to path, and its expected answers to path.expected
+func writeGenerated(path string, lines []string, expected expectedTotals) error {
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		return errors.New("fail to write generated schematic to " + path + " due to error " + err.Error())
+	}
+
+	content := fmt.Sprintf("part numbers sum\t%d\ngear ratios sum\t%d\n", expected.partNumbersSum, expected.gearRatiosSum)
+	if err := os.WriteFile(path+".expected", []byte(content), 0o644); err != nil {
+		return errors.New("fail to write expected answers due to error " + err.Error())
+	}
+	return nil
+}
+
+func runGenerate(args []string) error {
+
+	conf := generatorConfig{}
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	out := flags.String("out", "generated.txt", "file receiving the schematic, expected answers going to <out>.expected")
+	flags.Int64Var(&conf.seed, "seed", 1, "seed of the generator, the same seed giving the same schematic")
+	flags.IntVar(&conf.width, "width", 140, "number of columns")
+	flags.IntVar(&conf.height, "height", 140, "number of lines")
+	flags.Float64Var(&conf.numberDensity, "numbers", 0.1, "probability of a free cell starting a number")
+	flags.Float64Var(&conf.symbolDensity, "symbol-density", 0.05, "probability of a free cell holding a symbol")
+	flags.Float64Var(&conf.gearDensity, "gears", 0.3, "probability of a symbol being a *")
+	flags.StringVar(&conf.symbols, "symbols", defaultSymbols, "symbols other than * to draw from")
+	flags.IntVar(&conf.edgeCases, "edge-cases", 2, "times each edge case is planted")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	lines, expected, err := generateSchematic(conf)
+	if err != nil {
+		return err
+	}
+	if err := writeGenerated(*out, lines, expected); err != nil {
+		return err
+	}
+
+	fmt.Println("generated", len(lines), "lines to", *out, "and", *out+".expected")
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSchematic(t *testing.T) {
+	conf := generatorConfig{width: 60, height: 40, numberDensity: 0.15, symbolDensity: 0.08, gearDensity: 0.4, symbols: defaultSymbols, edgeCases: 2}
+	for seed := int64(1); seed <= 20; seed++ {
+		conf.seed = seed
+		lines, expected, err := generateSchematic(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lines) != conf.height || len(lines[0]) != conf.width {
+			t.Fatalf("Expected a %dx%d schematic, got %d lines of %d", conf.width, conf.height, len(lines), len(lines[0]))
+		}
+
+		// The solver must find the answers recorded for the schematic
+		partNumbersSum, gearRatiosSum := batchTotals(t, lines)
+		if partNumbersSum != expected.partNumbersSum || gearRatiosSum != expected.gearRatiosSum {
+			t.Errorf("For seed %d, expected %+v, but got %d and %d", seed, expected, partNumbersSum, gearRatiosSum)
+		}
+
+		// Numbers are planted against both edges
+		left, right := false, false
+		for _, line := range lines {
+			left = left || (line[0] >= '1' && line[0] <= '9')
+			right = right || (line[len(line)-1] >= '0' && line[len(line)-1] <= '9')
+		}
+		if !left || !right {
+			t.Errorf("For seed %d, expected numbers against both edges of %q", seed, lines)
+		}
+	}
+
+	// The same seed gives the same schematic
+	lines, _, err := generateSchematic(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _, err := generateSchematic(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lines, again) {
+		t.Error("Expected the same schematic for the same seed")
+	}
+}
+
+func TestGenerateSchematicEdgeCasesOnly(t *testing.T) {
+	// without random cells, the schematic only holds the edge cases
+	conf := generatorConfig{seed: 42, width: 60, height: 40, gearDensity: 0.4, symbols: "#", edgeCases: 1}
+
+	lines, expected, err := generateSchematic(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schematic := strings.Join(lines, "\n")
+	if strings.Count(schematic, "#") != 3 || strings.Count(schematic, "*") != 2 {
+		t.Errorf("Expected the symbols of the five edge cases, got %q", lines)
+	}
+	if expected.gearRatiosSum == 0 {
+		t.Errorf("Expected the gears of the edge cases, got %+v", expected)
+	}
+}
+
+func TestEdgeCaseGearSpanningNumber(t *testing.T) {
+	// a number around a gear is found by its start or end in the window of the gear,
+	// as getGears first did, unless it spans the whole window
+	missedByEnds := func(template []string) bool {
+		numbers, err := findEngineNumbers(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for line, indices := range findEngineSymbols(template) {
+			for indice, symbol := range indices {
+				window := []int{indice - 1, indice + 1}
+				for _, number := range numbers {
+					touching := number.line >= line-1 && number.line <= line+1 && number.startIndice <= window[1] && number.endIndice >= window[0]
+					if symbol == "*" && touching && !isNumberInInterval(number.startIndice, window) && !isNumberInInterval(number.endIndice, window) {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+
+	for _, edge := range edgeCases {
+		if !missedByEnds(edge.template) {
+			continue
+		}
+		// the gear still counts the number spanning its window
+		numbers, err := findEngineNumbers(edge.template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gears := getGears(findEngineSymbols(edge.template), numbers)
+		if len(gears) != 1 || gears[0].ratio != 11*11111 {
+			t.Errorf("For edge case %q, expected a gear of ratio %d, but got %+v", edge.template, 11*11111, gears)
+		}
+		return
+	}
+	t.Errorf("Expected an edge case with a number missed by the ends check, got %q", edgeCases)
+}
+
+func TestSolveGrid(t *testing.T) {
+	lines, err := readLinesFrom(strings.NewReader(exampleInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cells [][]byte
+	for _, line := range lines {
+		cells = append(cells, []byte(line))
+	}
+	expected := expectedTotals{partNumbersSum: 4361, gearRatiosSum: 467835}
+	if totals := solveGrid(cells); totals != expected {
+		t.Errorf("Expected %+v, but got %+v", expected, totals)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+)
+
+// neighbourhood kinds
+const (
+	neighbourhoodOrthogonal = "4"
+	neighbourhoodDiagonal   = "8"
+)
+
+// neighbourhood is the rule deciding which cells of the schematic touch each other
+type neighbourhood struct {
+	// an orthogonal neighbourhood reaches the cells at a Manhattan distance up to
+	// radius, a diagonal one the whole square at a Chebyshev distance up to radius
+	orthogonal bool
+	radius     int
+	// with wrap, each edge of the rows x columns schematic touches the opposite edge
+	wrap    bool
+	rows    int
+	columns int
+}
+
+// the puzzle rule, the 8 cells around each cell
+var defaultNeighbourhood = neighbourhood{radius: 1}
+
+// newNeighbourhood builds a neighbourhood of kind 4 or 8 for the schematic lines,
+// which give the size of the wrapped schematic
+func newNeighbourhood(kind string, radius int, wrap bool, lines []string) (neighbourhood, error) {
+
+	hood := neighbourhood{radius: radius, wrap: wrap}
+
+	switch kind {
+	case neighbourhoodOrthogonal:
+		hood.orthogonal = true
+	case neighbourhoodDiagonal:
+	default:
+		return hood, errors.New("unknown neighbourhood " + kind + ", expected " + neighbourhoodOrthogonal + " or " + neighbourhoodDiagonal)
+	}
+	if radius < 1 {
+		return hood, errors.New("invalid radius " + strconv.Itoa(radius) + ", expected at least 1")
+	}
+
+	if wrap {
+		hood.rows = len(lines)
+		for _, line := range lines {
+			if len(line) > hood.columns {
+				hood.columns = len(line)
+			}
+		}
+	}
+	return hood, nil
+}
+
+// distance returns the distance between two rows or two columns, going round the
+// schematic of the given size when it is shorter
+func (hood neighbourhood) distance(a int, b int, size int) int {
+	d := a - b
+	if d < 0 {
+		d = -d
+	}
+	if hood.wrap && size > 0 {
+		d %= size
+		if size-d < d {
+			d = size - d
+		}
+	}
+	return d
+}
+
+func (hood neighbourhood) touches(line int, indice int, otherLine int, otherIndice int) bool {
+	lineDistance := hood.distance(line, otherLine, hood.rows)
+	indiceDistance := hood.distance(indice, otherIndice, hood.columns)
+	if hood.orthogonal {
+		return lineDistance+indiceDistance <= hood.radius
+	}
+	return lineDistance <= hood.radius && indiceDistance <= hood.radius
+}
+
+// numberTouches tells if a digit of the number touches the cell
+func (hood neighbourhood) numberTouches(eNbr engineNumber, line int, indice int) bool {
+
+	if !hood.wrap && !hood.orthogonal {
+		return hood.distance(eNbr.line, line, hood.rows) <= hood.radius &&
+			isNumberInInterval(indice, []int{eNbr.startIndice - hood.radius, eNbr.endIndice + hood.radius})
+	}
+
+	for digit := eNbr.startIndice; digit <= eNbr.endIndice; digit++ {
+		if hood.touches(eNbr.line, digit, line, indice) {
+			return true
+		}
+	}
+	return false
+}
+
+// symbolLines returns the lines holding symbols the number may touch
+func (hood neighbourhood) symbolLines(eNbr engineNumber, symbolsMatrix map[int]map[int]string) []int {
+
+	var lines []int
+	seen := make(map[int]bool)
+
+	for line := eNbr.line - hood.radius; line <= eNbr.line+hood.radius; line++ {
+		symbolLine := line
+		if hood.wrap && hood.rows > 0 {
+			symbolLine = ((line % hood.rows) + hood.rows) % hood.rows
+		}
+		if _, ok := symbolsMatrix[symbolLine]; ok && !seen[symbolLine] {
+			seen[symbolLine] = true
+			lines = append(lines, symbolLine)
+		}
+	}
+	return lines
+}
+
+// isPartNumberWithin tells if a symbol is in the neighbourhood of the number
+func isPartNumberWithin(eNbr engineNumber, symbolsMatrix map[int]map[int]string, hood neighbourhood) bool {
+
+	for _, line := range hood.symbolLines(eNbr, symbolsMatrix) {
+		for indice := range symbolsMatrix[line] {
+			if hood.numberTouches(eNbr, line, indice) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getEnginPartNumbersWithin returns the numbers with a symbol in their neighbourhood
+func getEnginPartNumbersWithin(symbols map[int]map[int]string, numbers []engineNumber, hood neighbourhood) []int {
+	var partNumbers []int
+
+	for _, nb := range numbers {
+		if isPartNumberWithin(nb, symbols, hood) {
+			partNumbers = append(partNumbers, nb.number)
+		}
+	}
+
+	return partNumbers
+}
+
+// getGearsWithin returns the * symbols with exactly two numbers in their neighbourhood
+func getGearsWithin(symbolsMatrix map[int]map[int]string, engineNumbers []engineNumber, hood neighbourhood) []gear {
+
+	var gears []gear
+
+	for _, found := range locateGearsWithin(symbolsMatrix, engineNumbers, hood) {
+		gears = append(gears, found)
+	}
+
+	return gears
+}
+
+// locateGearsWithin returns the gears by the cell of their * symbol
+func locateGearsWithin(symbolsMatrix map[int]map[int]string, engineNumbers []engineNumber, hood neighbourhood) map[schematicCell]gear {
+
+	gears := make(map[schematicCell]gear)
+
+	for symbolLine, indiceMap := range symbolsMatrix {
+		for indice, symbol := range indiceMap {
+			if symbol != "*" {
+				continue
+			}
+			var numbers []int
+			for _, number := range engineNumbers {
+				if hood.numberTouches(number, symbolLine, indice) {
+					numbers = append(numbers, number.number)
+				}
+			}
+			if len(numbers) == 2 {
+				gears[schematicCell{symbolLine, indice}] = gear{
+					numbers: numbers,
+					ratio:   numbers[0] * numbers[1],
+				}
+			}
+		}
+	}
+
+	return gears
+}
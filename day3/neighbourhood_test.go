@@ -0,0 +1,202 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// referenceNeighbourhoodParts is a brute-force oracle for getEnginPartNumbersWithin,
+// looking for a symbol at every offset of the neighbourhood around each digit
+func referenceNeighbourhoodParts(symbols map[int]map[int]string, numbers []engineNumber, hood neighbourhood) []int {
+	var partNumbers []int
+	for _, nb := range numbers {
+		isPart := false
+		for digit := nb.startIndice; digit <= nb.endIndice; digit++ {
+			for dLine := -hood.radius; dLine <= hood.radius; dLine++ {
+				for dIndice := -hood.radius; dIndice <= hood.radius; dIndice++ {
+					if hood.orthogonal && abs(dLine)+abs(dIndice) > hood.radius {
+						continue
+					}
+					line, indice := nb.line+dLine, digit+dIndice
+					if hood.wrap {
+						line = ((line % hood.rows) + hood.rows) % hood.rows
+						indice = ((indice % hood.columns) + hood.columns) % hood.columns
+					}
+					if _, ok := symbols[line][indice]; ok {
+						isPart = true
+					}
+				}
+			}
+		}
+		if isPart {
+			partNumbers = append(partNumbers, nb.number)
+		}
+	}
+	return partNumbers
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func TestNewNeighbourhood(t *testing.T) {
+	lines := []string{"..", "....", "..."}
+
+	hood, err := newNeighbourhood(neighbourhoodOrthogonal, 2, true, lines)
+	expected := neighbourhood{orthogonal: true, radius: 2, wrap: true, rows: 3, columns: 4}
+	if err != nil || hood != expected {
+		t.Errorf("Expected %+v, but got %+v and error %v", expected, hood, err)
+	}
+
+	hood, err = newNeighbourhood(neighbourhoodDiagonal, 1, false, lines)
+	if err != nil || hood != defaultNeighbourhood {
+		t.Errorf("Expected the default neighbourhood, but got %+v and error %v", hood, err)
+	}
+
+	if _, err := newNeighbourhood("6", 1, false, lines); err == nil {
+		t.Error("Expected an error for an unknown neighbourhood")
+	}
+	if _, err := newNeighbourhood(neighbourhoodDiagonal, 0, false, lines); err == nil {
+		t.Error("Expected an error for a radius of 0")
+	}
+}
+
+func TestNeighbourhoodTouches(t *testing.T) {
+	orthogonal := neighbourhood{orthogonal: true, radius: 1}
+	wide := neighbourhood{radius: 2}
+	wrapped := neighbourhood{radius: 1, wrap: true, rows: 5, columns: 10}
+
+	testCases := []struct {
+		hood     neighbourhood
+		cells    [4]int
+		expected bool
+	}{
+		{defaultNeighbourhood, [4]int{2, 2, 3, 3}, true},
+		{defaultNeighbourhood, [4]int{2, 2, 4, 2}, false},
+		{orthogonal, [4]int{2, 2, 3, 3}, false},
+		{orthogonal, [4]int{2, 2, 2, 3}, true},
+		{wide, [4]int{2, 2, 4, 0}, true},
+		{wide, [4]int{2, 2, 5, 2}, false},
+		{neighbourhood{orthogonal: true, radius: 2}, [4]int{2, 2, 3, 3}, true},
+		{neighbourhood{orthogonal: true, radius: 2}, [4]int{2, 2, 4, 3}, false},
+		{wrapped, [4]int{0, 0, 4, 9}, true},
+		{wrapped, [4]int{0, 0, 4, 8}, false},
+		{defaultNeighbourhood, [4]int{0, 0, 4, 9}, false},
+	}
+
+	for _, testCase := range testCases {
+		cells := testCase.cells
+		if touches := testCase.hood.touches(cells[0], cells[1], cells[2], cells[3]); touches != testCase.expected {
+			t.Errorf("For neighbourhood %+v and cells %v, expected %t, but got %t", testCase.hood, cells, testCase.expected, touches)
+		}
+	}
+}
+
+func TestGetEnginPartNumbersWithin(t *testing.T) {
+	lines, err := readLinesFrom(strings.NewReader(exampleInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	symbols := findEngineSymbols(lines)
+	numbers, err := findEngineNumbers(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		kind          string
+		radius        int
+		wrap          bool
+		partNumbers   int
+		gearRatiosSum int
+	}{
+		{neighbourhoodDiagonal, 1, false, 4361, 467835},
+		{neighbourhoodOrthogonal, 1, false, 2547, 0},
+		// 114 and 58 reach a symbol two cells away, every star then touching three numbers
+		{neighbourhoodDiagonal, 2, false, 4533, 0},
+		{neighbourhoodDiagonal, 1, true, 4361, 467835},
+	}
+
+	for _, testCase := range testCases {
+		hood, err := newNeighbourhood(testCase.kind, testCase.radius, testCase.wrap, lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		partNumbers := sumInts(getEnginPartNumbersWithin(symbols, numbers, hood))
+		gearRatiosSum := 0
+		for _, found := range getGearsWithin(symbols, numbers, hood) {
+			gearRatiosSum += found.ratio
+		}
+		if partNumbers != testCase.partNumbers || gearRatiosSum != testCase.gearRatiosSum {
+			t.Errorf("For neighbourhood %+v, expected %d and %d, but got %d and %d",
+				hood, testCase.partNumbers, testCase.gearRatiosSum, partNumbers, gearRatiosSum)
+		}
+	}
+}
+
+func TestGetGearsWithinWrap(t *testing.T) {
+	// the star only touches 5 through the left edge
+	lines := []string{"...*", "....", "5..7"}
+	symbols := findEngineSymbols(lines)
+	numbers, err := findEngineNumbers(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hood, err := newNeighbourhood(neighbourhoodDiagonal, 1, true, lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gears := getGearsWithin(symbols, numbers, hood)
+	if !reflect.DeepEqual(gears, []gear{{numbers: []int{5, 7}, ratio: 35}}) {
+		t.Errorf("Expected the gear 5 * 7 across the edges, but got %v", gears)
+	}
+	if gears := getGears(symbols, numbers); gears != nil {
+		t.Errorf("Expected no gear without wrap, but got %v", gears)
+	}
+}
+
+func TestNeighbourhoodMatchesReference(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	alphabet := ".....0123456789*#"
+
+	for i := 0; i < 500; i++ {
+		lines := make([]string, 1+rnd.Intn(7))
+		width := 1 + rnd.Intn(10)
+		for row := range lines {
+			var line strings.Builder
+			for col := 0; col < width; col++ {
+				line.WriteByte(alphabet[rnd.Intn(len(alphabet))])
+			}
+			lines[row] = line.String()
+		}
+		symbols := findEngineSymbols(lines)
+		numbers, err := findEngineNumbers(lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		kind := neighbourhoodDiagonal
+		if rnd.Intn(2) == 0 {
+			kind = neighbourhoodOrthogonal
+		}
+		hood, err := newNeighbourhood(kind, 1+rnd.Intn(3), rnd.Intn(2) == 0, lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		partNumbers := getEnginPartNumbersWithin(symbols, numbers, hood)
+		expected := referenceNeighbourhoodParts(symbols, numbers, hood)
+		sort.Ints(partNumbers)
+		sort.Ints(expected)
+		if !reflect.DeepEqual(partNumbers, expected) {
+			t.Errorf("For schematic %q and neighbourhood %+v, expected %v, but got %v", lines, hood, expected, partNumbers)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// isSign tells if the character at indice is a - or + directly before the digits of
+// a number, a character right after another number staying a symbol between both
+func isSign(line string, indice int) bool {
+
+	if line[indice] != '-' && line[indice] != '+' {
+		return false
+	}
+
+	next, _ := utf8.DecodeRuneInString(line[indice+1:])
+	if !unicode.IsDigit(next) {
+		return false
+	}
+
+	previous, _ := utf8.DecodeLastRuneInString(line[:indice])
+	return !unicode.IsDigit(previous)
+}
+
+// findSignedEngineNumbers finds the engine numbers like findEngineNumbers, a sign
+// directly before the digits being part of the number
+func findSignedEngineNumbers(lines []string) ([]engineNumber, error) {
+
+	engineNumbers, err := findEngineNumbers(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, number := range engineNumbers {
+		line := lines[number.line]
+		if number.startIndice == 0 || !isSign(line, number.startIndice-1) {
+			continue
+		}
+		number.startIndice--
+		number.numberStr = line[number.startIndice:number.startIndice+1] + number.numberStr
+		if line[number.startIndice] == '-' {
+			number.number = -number.number
+		}
+		engineNumbers[i] = number
+	}
+
+	return engineNumbers, nil
+}
+
+// findSignedEngineSymbols finds the symbols like findEngineSymbols, leaving out the
+// signs of the numbers
+func findSignedEngineSymbols(lines []string) map[int]map[int]string {
+
+	matrix := findEngineSymbols(lines)
+
+	for lineNb, indiceMap := range matrix {
+		for indice := range indiceMap {
+			if isSign(lines[lineNb], indice) {
+				delete(indiceMap, indice)
+			}
+		}
+		if len(indiceMap) == 0 {
+			delete(matrix, lineNb)
+		}
+	}
+
+	return matrix
+}
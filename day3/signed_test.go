@@ -0,0 +1,100 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsSign(t *testing.T) {
+	testCases := []struct {
+		line     string
+		indice   int
+		expected bool
+	}{
+		{"-42", 0, true},
+		{"+42", 0, true},
+		{".-42", 1, true},
+		{"*-42", 1, true},
+		{"--42", 0, false},
+		{"--42", 1, true},
+		{"12-34", 2, false},
+		{"-.42", 0, false},
+		{"42-", 2, false},
+		{"*42", 0, false},
+		{"é-1", 2, true},
+	}
+
+	for _, testCase := range testCases {
+		if result := isSign(testCase.line, testCase.indice); result != testCase.expected {
+			t.Errorf("For line %q at %d, expected %t, but got %t", testCase.line, testCase.indice, testCase.expected, result)
+		}
+	}
+}
+
+func TestFindSignedEngineNumbers(t *testing.T) {
+	lines := []string{"-42.+7*", "12-34", "--5"}
+	numbers, err := findSignedEngineNumbers(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []engineNumber{
+		{numberStr: "-42", number: -42, startIndice: 0, endIndice: 2, line: 0},
+		{numberStr: "+7", number: 7, startIndice: 4, endIndice: 5, line: 0},
+		{numberStr: "12", number: 12, startIndice: 0, endIndice: 1, line: 1},
+		{numberStr: "34", number: 34, startIndice: 3, endIndice: 4, line: 1},
+		{numberStr: "-5", number: -5, startIndice: 1, endIndice: 2, line: 2},
+	}
+	if !reflect.DeepEqual(numbers, expected) {
+		t.Errorf("Expected %v, but got %v", expected, numbers)
+	}
+}
+
+func TestFindSignedEngineSymbols(t *testing.T) {
+	lines := []string{"-42.+7*", "12-34", "--5", "+..."}
+	symbols := findSignedEngineSymbols(lines)
+
+	expected := map[int]map[int]string{
+		0: {6: "*"},
+		1: {2: "-"},
+		2: {0: "-"},
+		3: {0: "+"},
+	}
+	if !reflect.DeepEqual(symbols, expected) {
+		t.Errorf("Expected %v, but got %v", expected, symbols)
+	}
+
+	// a line of signs only has no symbol left
+	if symbols := findSignedEngineSymbols([]string{"-1.+2"}); len(symbols) != 0 {
+		t.Errorf("Expected no symbol, but got %v", symbols)
+	}
+}
+
+func TestSignedPartsAndGears(t *testing.T) {
+	lines := []string{
+		"467..-14..",
+		"...*......",
+		"..+35.633.",
+		"......#...",
+		"-2*-3.....",
+	}
+	symbols := findSignedEngineSymbols(lines)
+	numbers, err := findSignedEngineNumbers(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// -14 only touched its own sign, +35 touches the gear and not its sign
+	partNumbers := getEnginPartNumbersWithin(symbols, numbers, defaultNeighbourhood)
+	if !reflect.DeepEqual(partNumbers, []int{467, 35, 633, -2, -3}) {
+		t.Errorf("Expected part numbers 467, 35, 633, -2 and -3, but got %v", partNumbers)
+	}
+
+	gearRatiosSum := 0
+	for _, found := range getGears(symbols, numbers) {
+		gearRatiosSum += found.ratio
+	}
+	if gearRatiosSum != 467*35+6 {
+		t.Errorf("Expected gear ratios sum %d, but got %d", 467*35+6, gearRatiosSum)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"sort"
+)
+
+// longest row the streaming scanner accepts
+const maxRowLength = 16 * 1024 * 1024
+
+// schematicTotals are the answers for the rows scanned so far
+type schematicTotals struct {
+	rows           int
+	partNumbersSum int
+	gearRatiosSum  int
+}
+
+// scanWindow holds the symbols and numbers of the last three rows read, a number
+// only touching symbols of the row above, its own row and the row below
+type scanWindow struct {
+	symbols map[int]map[int]string
+	numbers []engineNumber
+}
+
+// push adds the symbols and numbers of a row to the window
+func (window *scanWindow) push(lineNb int, line string) error {
+
+	if rowSymbols, ok := findEngineSymbols([]string{line})[0]; ok {
+		window.symbols[lineNb] = rowSymbols
+	}
+
+	numbers, err := findEngineNumbers([]string{line})
+	if err != nil {
+		return err
+	}
+	for _, number := range numbers {
+		number.line = lineNb
+		window.numbers = append(window.numbers, number)
+	}
+	return nil
+}
+
+// settle emits the part numbers and gears of a row once the row below it is in the
+// window, then forgets the row above it which no longer touches any unsettled row
+func (window *scanWindow) settle(lineNb int, totals *schematicTotals, emitPart func(engineNumber), emitGear func(int, gear)) {
+
+	for _, number := range window.numbers {
+		if number.line == lineNb && isPartNumber(number, window.symbols) {
+			number.isPartNumber = true
+			totals.partNumbersSum += number.number
+			if emitPart != nil {
+				emitPart(number)
+			}
+		}
+	}
+
+	// gears are emitted from left to right
+	var stars []int
+	for indice, symbol := range window.symbols[lineNb] {
+		if symbol == "*" {
+			stars = append(stars, indice)
+		}
+	}
+	sort.Ints(stars)
+
+	possibleLines := []int{lineNb - 1, lineNb, lineNb + 1}
+	for _, indice := range stars {
+		numbers := findEngineNumbersInInterval(window.numbers, possibleLines, []int{indice - 1, indice + 1})
+		if len(numbers) == 2 {
+			found := gear{numbers: numbers, ratio: numbers[0] * numbers[1]}
+			totals.gearRatiosSum += found.ratio
+			if emitGear != nil {
+				emitGear(lineNb, found)
+			}
+		}
+	}
+
+	delete(window.symbols, lineNb-1)
+	kept := window.numbers[:0]
+	for _, number := range window.numbers {
+		if number.line >= lineNb {
+			kept = append(kept, number)
+		}
+	}
+	window.numbers = kept
+}
+
+// scanSchematic reads the schematic one row at a time, keeping three rows in memory,
+// and calls emitPart for each part number and emitGear for each gear as soon as the
+// rows around them are read. Numbers and gears come out row by row, from left to right.
+func scanSchematic(reader io.Reader, emitPart func(engineNumber), emitGear func(int, gear)) (schematicTotals, error) {
+
+	var totals schematicTotals
+	window := scanWindow{symbols: make(map[int]map[int]string)}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRowLength)
+
+	for scanner.Scan() {
+		if err := window.push(totals.rows, scanner.Text()); err != nil {
+			return totals, err
+		}
+		if totals.rows > 0 {
+			window.settle(totals.rows-1, &totals, emitPart, emitGear)
+		}
+		totals.rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return totals, errors.New("fail to read lines due to error " + err.Error())
+	}
+
+	// the last row has no row below it
+	if totals.rows > 0 {
+		window.settle(totals.rows-1, &totals, emitPart, emitGear)
+	}
+
+	return totals, nil
+}
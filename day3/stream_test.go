@@ -0,0 +1,116 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// batchTotals solves a schematic loaded whole
+func batchTotals(t *testing.T, lines []string) (int, int) {
+	symbols := findEngineSymbols(lines)
+	numbers, err := findEngineNumbers(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	partNumbers, err := getEnginPartNumbers(lines, symbols, numbers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gearRatiosSum := 0
+	for _, found := range getGears(symbols, numbers) {
+		gearRatiosSum += found.ratio
+	}
+	return sumInts(partNumbers), gearRatiosSum
+}
+
+func TestScanSchematic(t *testing.T) {
+	var partNumbers []int
+	var gearLines []int
+	totals, err := scanSchematic(strings.NewReader(exampleInput),
+		func(number engineNumber) {
+			if !number.isPartNumber {
+				t.Errorf("Expected number %d to be flagged as a part number", number.number)
+			}
+			partNumbers = append(partNumbers, number.number)
+		},
+		func(lineNb int, found gear) { gearLines = append(gearLines, lineNb) },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := schematicTotals{rows: 10, partNumbersSum: 4361, gearRatiosSum: 467835}
+	if totals != expected {
+		t.Errorf("Expected totals %+v, but got %+v", expected, totals)
+	}
+	if !reflect.DeepEqual(partNumbers, []int{467, 35, 633, 617, 592, 755, 664, 598}) {
+		t.Errorf("Expected part numbers in reading order, but got %v", partNumbers)
+	}
+	if !reflect.DeepEqual(gearLines, []int{1, 8}) {
+		t.Errorf("Expected gears on lines 1 and 8, but got %v", gearLines)
+	}
+
+	// nothing to emit is not an error
+	totals, err = scanSchematic(strings.NewReader(""), nil, nil)
+	if err != nil || totals != (schematicTotals{}) {
+		t.Errorf("For an empty schematic, expected no totals, but got %+v and %v", totals, err)
+	}
+}
+
+func TestScanSchematicMatchesBatch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	alphabet := "....0123456789*#+"
+
+	for i := 0; i < 500; i++ {
+		lines := make([]string, 1+rnd.Intn(8))
+		width := 1 + rnd.Intn(12)
+		for row := range lines {
+			var line strings.Builder
+			for col := 0; col < width; col++ {
+				line.WriteByte(alphabet[rnd.Intn(len(alphabet))])
+			}
+			lines[row] = line.String()
+		}
+
+		totals, err := scanSchematic(strings.NewReader(strings.Join(lines, "\n")), nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		partNumbersSum, gearRatiosSum := batchTotals(t, lines)
+		if totals.partNumbersSum != partNumbersSum || totals.gearRatiosSum != gearRatiosSum {
+			t.Errorf("For schematic %q, expected %d and %d, but got %+v", lines, partNumbersSum, gearRatiosSum, totals)
+		}
+	}
+}
+
+func TestScanWindowForgetsSettledRows(t *testing.T) {
+	var totals schematicTotals
+	window := scanWindow{symbols: make(map[int]map[int]string)}
+
+	for lineNb := 0; lineNb < 20; lineNb++ {
+		if err := window.push(lineNb, "1*2.#3"); err != nil {
+			t.Fatal(err)
+		}
+		if lineNb > 0 {
+			window.settle(lineNb-1, &totals, nil, nil)
+		}
+		if len(window.symbols) > 2 || len(window.numbers) > 6 {
+			t.Fatalf("At line %d, expected at most two rows in the window, but got %d symbol rows and %d numbers",
+				lineNb, len(window.symbols), len(window.numbers))
+		}
+	}
+}
+
+func TestScanSchematicLongRow(t *testing.T) {
+	// rows longer than the default scanner buffer
+	row := strings.Repeat(".", 100000) + "12*3"
+	totals, err := scanSchematic(strings.NewReader(row), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totals.partNumbersSum != 15 || totals.gearRatiosSum != 36 {
+		t.Errorf("Expected 15 and 36, but got %+v", totals)
+	}
+}
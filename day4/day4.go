@@ -2,36 +2,80 @@ package main
 
 import (
 	"bufio"
+	_ "embed"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"math/bits"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type card struct {
 	cardNumber   int
 	winningNbrs  []int
 	numbers      []int
-	points       int
 	winningTimes int
 }
 
+//go:embed input_test.txt
+var exampleInput string
+
+// defaultInputPath returns the input.txt stored next to this source file, so the
+// solver finds its puzzle input whatever the current working directory is
+func defaultInputPath() string {
+	_, source, _, ok := runtime.Caller(0)
+	if ok {
+		path := filepath.Join(filepath.Dir(source), "input.txt")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return "./input.txt"
+}
+
 // read each line of file and add to a slice
 func readLines(filename string) ([]string, error) {
-	lines := make([]string, 0)
 	file, err := os.Open(filename)
 	if err != nil {
-		return lines, errors.New("fail to read lines from " + filename + " due to error " + err.Error())
+		return make([]string, 0), errors.New("fail to read lines from " + filename + " due to error " + err.Error())
 	}
 	defer file.Close()
-	scanner := bufio.NewScanner(file)
+	return readLinesFrom(file)
+}
+
+// read each line of a reader and add to a slice
+func readLinesFrom(reader io.Reader) ([]string, error) {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
 		lines = append(lines, scanner.Text())
 	}
+	if err := scanner.Err(); err != nil {
+		return lines, errors.New("fail to read lines due to error " + err.Error())
+	}
 	return lines, nil
 }
 
+// loadInput returns the puzzle lines, read from the example bundled with the
+// solver, from stdin when inputPath is "-", or from the inputPath file
+func loadInput(inputPath string, example bool) ([]string, error) {
+	if example {
+		return readLinesFrom(strings.NewReader(exampleInput))
+	}
+	if inputPath == "-" {
+		return readLinesFrom(os.Stdin)
+	}
+	return readLines(inputPath)
+}
+
 func parseNumbers(s string) ([]int, error) {
 	var result []int
 
@@ -57,6 +101,9 @@ func parseLine(line string) (card, error) {
 	}
 
 	// Extract card number
+	if !strings.HasPrefix(parts[0], "Card ") {
+		return result, errors.New("invalid card header: " + parts[0])
+	}
 	cardNumber, err := strconv.Atoi(strings.TrimSpace(parts[0][len("Card "):]))
 	if err != nil {
 		return result, fmt.Errorf("failed to parse card number: %v", err)
@@ -105,6 +152,8 @@ func countNumbersInSlice(slice1, slice2 []int) int {
 	return count
 }
 
+// doubleOnEachMatch returns the points of a card, which overflow an int past 63
+// matches, as checked by countPointsInt
 func doubleOnEachMatch(matchNbr int) int {
 
 	if matchNbr < 1 {
@@ -120,89 +169,199 @@ func doubleOnEachMatch(matchNbr int) int {
 }
 
 func processCardsPoints(cards []card) []card {
+	// counting matches as a set never fails
+	pocceedCards, _ := processCardsPointsWith(cards, matchSet)
+	return pocceedCards
+}
+
+func getCards(lines []string) ([]card, error) {
+	return getCardsWith(lines, matchSet)
+}
 
-	var pocceedCards []card
+// countPointsInt sums the points of the cards, doubling on each match. It gives up
+// as soon as the points of a card or their sum overflow an int.
+func countPointsInt(cards deck) (int, bool) {
 
-	for _, card := range cards {
-		card.winningTimes = countNumbersInSlice(card.winningNbrs, card.numbers)
-		card.points = doubleOnEachMatch(card.winningTimes)
-		pocceedCards = append(pocceedCards, card)
+	total := 0
+
+	for _, current := range cards.all() {
+		if current.winningTimes > bits.UintSize-1 {
+			return 0, false
+		}
+
+		points := doubleOnEachMatch(current.winningTimes)
+		if total > math.MaxInt-points {
+			return 0, false
+		}
+		total += points
 	}
 
-	return pocceedCards
+	return total, true
 }
 
-func getCards(lines []string) ([]card, error) {
-
-	var cards []card
+// countPointsBig sums the points of the cards like countPointsInt, with exact
+// math/big points
+func countPointsBig(cards deck) *big.Int {
 
-	for _, line := range lines {
+	total := new(big.Int)
 
-		card, err := parseLine(line)
-		if err != nil {
-			return nil, errors.New("parse line fail with error: " + err.Error())
+	for _, current := range cards.all() {
+		if current.winningTimes > 0 {
+			total.Add(total, new(big.Int).Lsh(big.NewInt(1), uint(current.winningTimes-1)))
 		}
-		cards = append(cards, card)
+	}
+
+	return total
+}
 
+// processPart1 returns the sum of the cards points, summed with ints and summed
+// again with math/big when they overflow, or always with math/big when alwaysBig
+// is set
+func processPart1(cards deck, alwaysBig bool) *big.Int {
+
+	if !alwaysBig {
+		if total, ok := countPointsInt(cards); ok {
+			return big.NewInt(int64(total))
+		}
 	}
 
-	return processCardsPoints(cards), nil
+	return countPointsBig(cards)
 }
 
-func sortCardsByNumber(cards []card) map[int][]card {
+// countCardsInt counts the instances of each card in card number order, each of them
+// winning a copy of the next cards for each match. It gives up as soon as a count
+// overflows an int.
+func countCardsInt(cards deck) (int, bool) {
 
-	cardsSorted := make(map[int][]card)
+	instances := make([]int, cards.len())
+	total := 0
 
-	for _, curentCard := range cards {
+	for i, current := range cards.all() {
+		count := instances[i] + 1
+		if count < 1 {
+			return 0, false
+		}
 
-		cardsSorted[curentCard.cardNumber] = []card{curentCard}
+		start, end := cards.indexRange(current.cardNumber+1, current.cardNumber+current.winningTimes)
+		for j := start; j < end; j++ {
+			if instances[j] > math.MaxInt-count {
+				return 0, false
+			}
+			instances[j] += count
+		}
 
+		if total > math.MaxInt-count {
+			return 0, false
+		}
+		total += count
 	}
-	return cardsSorted
+
+	return total, true
 }
 
-func processPart2(sortedCards map[int][]card) int {
+// countCardsBig counts the instances of each card like countCardsInt, with exact
+// math/big counts
+func countCardsBig(cards deck) *big.Int {
+	total, _ := countCardsBigWith(cards, nil)
+	return total
+}
 
-	cardNb := len(sortedCards)
-	totalCards := sortedCards
+// countCardsBigWith counts the cards like countCardsBig, calling step, when not nil,
+// after each card with its index, the index range of the cards winning its copies,
+// and the instances and total so far, which step must copy to keep them. It stops
+// on the first error of step.
+func countCardsBigWith(cards deck, step func(index, start, end int, instances []*big.Int, total *big.Int) error) (*big.Int, error) {
 
-	//get all cards
-	for _, cardTbl := range totalCards {
-		for _, card := range cardTbl {
-			fmt.Println("I've card number", card.cardNumber, "with", card.winningTimes, "winning numbers")
-			for i := 1; i <= card.winningTimes; i++ {
-				cardNb++
-				fmt.Println("I add card number", card.cardNumber+i)
-				totalCards[card.cardNumber+i] = append(totalCards[card.cardNumber+i], sortedCards[card.cardNumber+i][0])
+	instances := make([]*big.Int, cards.len())
+	for i := range instances {
+		instances[i] = big.NewInt(1)
+	}
+	total := new(big.Int)
+
+	for i, current := range cards.all() {
+		start, end := cards.indexRange(current.cardNumber+1, current.cardNumber+current.winningTimes)
+		for j := start; j < end; j++ {
+			instances[j].Add(instances[j], instances[i])
+		}
+
+		total.Add(total, instances[i])
+
+		if step != nil {
+			if err := step(i, start, end, instances, total); err != nil {
+				return total, err
 			}
 		}
 	}
 
-	return cardNb
+	return total, nil
+}
+
+// processPart2 returns the total number of scratchcards, counted with ints and
+// counted again with math/big when they overflow, or always with math/big when
+// alwaysBig is set. Copies of cards past the end of the table are not won.
+func processPart2(cards deck, alwaysBig bool) *big.Int {
+
+	if !alwaysBig {
+		if total, ok := countCardsInt(cards); ok {
+			return big.NewInt(int64(total))
+		}
+	}
+
+	return countCardsBig(cards)
 }
 
 func main() {
 
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := runGenerate(os.Args[2:]); err != nil {
+			panic(err.Error())
+		}
+		return
+	}
+
+	inputPath := flag.String("input", defaultInputPath(), "puzzle input file, or - to read stdin")
+	example := flag.Bool("example", false, "solve the example bundled with the solver instead of the input")
+	mode := flag.String("match", matchSet, "how repeated numbers match, "+matchSet+", "+matchMultiset+" for the multiset intersection, or "+matchStrict+" to reject them")
+	alwaysBig := flag.Bool("big", false, "always count the points and the cards with math/big, instead of only once an int overflows")
+	events := flag.Bool("simulate", false, "print each step of the copy counting as JSON lines instead of the answers")
+	animation := flag.Bool("animate", false, "animate each step of the copy counting in the terminal instead of printing the answers")
+	delay := flag.Duration("delay", 300*time.Millisecond, "time between two steps of the animation")
+	flag.Parse()
+
 	//read input file
-	lines, err := readLines("./input_test.txt")
+	lines, err := loadInput(*inputPath, *example)
 	if err != nil {
 		panic(err.Error())
 	}
 
-	cards, err := getCards(lines)
+	cards, err := getCardsWith(lines, *mode)
 	if err != nil {
 		panic(err.Error())
 	}
 
-	pointsSum := 0
-	for _, card := range cards {
-		pointsSum += card.points
+	sortedCards := newDeck(cards)
+	if err := sortedCards.validate(); err != nil {
+		panic(err.Error())
+	}
+	if missing := sortedCards.gaps(); len(missing) > 0 {
+		fmt.Fprintln(os.Stderr, "cards", missing, "are missing, their copies are not won")
 	}
 
-	fmt.Println("Part1 - Sum of cards points: ", pointsSum)
+	if *events {
+		if err := simulate(sortedCards, writeEvents(os.Stdout)); err != nil {
+			panic(err.Error())
+		}
+		return
+	}
+	if *animation {
+		if err := simulate(sortedCards, animate(os.Stdout, sortedCards, animationWidth, *delay)); err != nil {
+			panic(err.Error())
+		}
+		return
+	}
 
-	sortedCards := sortCardsByNumber(cards)
+	fmt.Println("Part1 - Sum of cards points: ", processPart1(sortedCards, *alwaysBig))
 
-	fmt.Println("Part2 - Sum of cards: ", processPart2(sortedCards))
+	fmt.Println("Part2 - Sum of cards: ", processPart2(sortedCards, *alwaysBig))
 
 }
@@ -2,8 +2,11 @@ package main
 
 import (
 	"fmt"
+	"math/big"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -41,6 +44,48 @@ func TestReadLines(t *testing.T) {
 	}
 }
 
+func TestLoadInput(t *testing.T) {
+	// Create a temporary file with sample content
+	tmpfile, err := os.CreateTemp("", "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpfile.Name()) // clean up
+
+	if _, err := tmpfile.WriteString("Line 1\nLine 2"); err != nil {
+		t.Fatal(err)
+	}
+	tmpfile.Close()
+
+	// Test reading the input from a file path
+	lines, err := loadInput(tmpfile.Name(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lines, []string{"Line 1", "Line 2"}) {
+		t.Errorf("Expected file lines, but got %v", lines)
+	}
+
+	// Test reading the bundled example, which must ignore the input path
+	lines, err = loadInput("nonexistentfile.txt", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) == 0 {
+		t.Error("Expected the bundled example lines, but got none")
+	}
+}
+
+func TestDefaultInputPath(t *testing.T) {
+	path := defaultInputPath()
+	if filepath.Base(path) != "input.txt" {
+		t.Errorf("Expected a path to input.txt, but got %s", path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected default input %s to exist, but got %v", path, err)
+	}
+}
+
 func TestParseNumbers(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -105,6 +150,25 @@ func TestParseLine(t *testing.T) {
 	}
 }
 
+func TestParseLineInvalid(t *testing.T) {
+	tests := []string{
+		":-1\xff\t+", // found by FuzzParseLine, used to panic on the short header
+		"Game 1: 1 2 | 3 4",
+		"Card 1 1 2 | 3 4",
+		"Card x: 1 2 | 3 4",
+		"Card 1: 1 2 3 4",
+		"Card 1: 1 a | 3 4",
+	}
+
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			if _, err := parseLine(test); err == nil {
+				t.Errorf("Expected an error for line %q", test)
+			}
+		})
+	}
+}
+
 func TestCountNumbersInSlice(t *testing.T) {
 	tests := []struct {
 		slice1   []int
@@ -154,15 +218,15 @@ func TestDoubleOnEachMatch(t *testing.T) {
 
 func TestProcessCardsPoints(t *testing.T) {
 	cards := []card{
-		{1, []int{41, 48, 83, 86, 17}, []int{83, 86, 6, 31, 17, 9, 48, 53}, 0, 0},
-		{2, []int{10, 20, 30}, []int{20, 30, 40, 50}, 0, 0},
-		{3, []int{1, 2, 3}, []int{4, 5, 6}, 0, 0},
+		{1, []int{41, 48, 83, 86, 17}, []int{83, 86, 6, 31, 17, 9, 48, 53}, 0},
+		{2, []int{10, 20, 30}, []int{20, 30, 40, 50}, 0},
+		{3, []int{1, 2, 3}, []int{4, 5, 6}, 0},
 	}
 
 	expected := []card{
-		{1, []int{41, 48, 83, 86, 17}, []int{83, 86, 6, 31, 17, 9, 48, 53}, 8, 4},
-		{2, []int{10, 20, 30}, []int{20, 30, 40, 50}, 2, 2},
-		{3, []int{1, 2, 3}, []int{4, 5, 6}, 0, 0},
+		{1, []int{41, 48, 83, 86, 17}, []int{83, 86, 6, 31, 17, 9, 48, 53}, 4},
+		{2, []int{10, 20, 30}, []int{20, 30, 40, 50}, 2},
+		{3, []int{1, 2, 3}, []int{4, 5, 6}, 0},
 	}
 
 	result := processCardsPoints(cards)
@@ -171,3 +235,171 @@ func TestProcessCardsPoints(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expected, result)
 	}
 }
+
+// benchmarkLines returns the puzzle input used by the benchmarks
+func benchmarkLines(b *testing.B) []string {
+	lines, err := readLines(defaultInputPath())
+	if err != nil {
+		b.Skip("no puzzle input to benchmark: " + err.Error())
+	}
+	return lines
+}
+
+// benchmarkCards parses the cards of the benchmark input, leaving the match counting
+// to the solve phase
+func benchmarkCards(b *testing.B, lines []string) []card {
+	var cards []card
+	for _, line := range lines {
+		card, err := parseLine(line)
+		if err != nil {
+			b.Fatal(err)
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+func BenchmarkPart1(b *testing.B) {
+	lines := benchmarkLines(b)
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchmarkCards(b, lines)
+		}
+	})
+
+	cards := benchmarkCards(b, lines)
+	b.Run("solve", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			processPart1(newDeck(processCardsPoints(cards)), false)
+		}
+	})
+}
+
+func TestProcessPart1Overflow(t *testing.T) {
+	// the first cards match 65 numbers, each worth 2^64 points
+	conf := generatorConfig{seed: 42, cards: 70, winning: 70, drawn: 80, minNumber: 1, maxNumber: 200, matchWeights: make([]int, 66)}
+	conf.matchWeights[65] = 1
+
+	lines, expected, err := generateCards(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected.pointsSum.Cmp(new(big.Int).Lsh(big.NewInt(1), 63)) <= 0 {
+		t.Fatalf("Expected more than 2^63 points, got %s", expected.pointsSum)
+	}
+
+	cards, err := getCards(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortedCards := newDeck(cards)
+
+	if _, ok := countPointsInt(sortedCards); ok {
+		t.Error("Expected the int points sum to overflow")
+	}
+	for _, alwaysBig := range []bool{false, true} {
+		if total := processPart1(sortedCards, alwaysBig); total.Cmp(expected.pointsSum) != 0 {
+			t.Errorf("With alwaysBig %t, expected %s points, got %s", alwaysBig, expected.pointsSum, total)
+		}
+	}
+
+	// the example fits an int
+	cards, err = getCards(strings.Split(strings.TrimSpace(exampleInput), "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total, ok := countPointsInt(newDeck(cards)); !ok || total != 13 {
+		t.Errorf("For the example, expected 13 points, got %d and %t", total, ok)
+	}
+}
+
+func TestProcessPart2(t *testing.T) {
+	cards, err := getCards(strings.Split(strings.TrimSpace(exampleInput), "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, alwaysBig := range []bool{false, true} {
+		if total := processPart2(newDeck(cards), alwaysBig); total.Cmp(big.NewInt(30)) != 0 {
+			t.Errorf("For the example with alwaysBig %t, expected 30 cards, got %s", alwaysBig, total)
+		}
+	}
+
+	// copies of missing cards are not won
+	gaps := newDeck([]card{
+		{cardNumber: 4},
+		{cardNumber: 1, winningTimes: 3},
+		{cardNumber: 2, winningTimes: 1},
+	})
+	if total := processPart2(gaps, false); total.Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("For cards with a gap, expected 5 cards, got %s", total)
+	}
+}
+
+func TestProcessPart2Overflow(t *testing.T) {
+	// every card winning copies of the next ten cards, the count roughly doubling on each card
+	conf := generatorConfig{seed: 42, cards: 120, winning: 10, drawn: 25, minNumber: 1, maxNumber: 99, matchWeights: []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}}
+
+	lines, expected, err := generateCards(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected.cardsCount.Cmp(new(big.Int).Lsh(big.NewInt(1), 63)) <= 0 {
+		t.Fatalf("Expected a deck of more than 2^63 cards, got %s", expected.cardsCount)
+	}
+
+	cards, err := getCards(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortedCards := newDeck(cards)
+
+	if _, ok := countCardsInt(sortedCards); ok {
+		t.Error("Expected the int count to overflow")
+	}
+	for _, alwaysBig := range []bool{false, true} {
+		if total := processPart2(sortedCards, alwaysBig); total.Cmp(expected.cardsCount) != 0 {
+			t.Errorf("With alwaysBig %t, expected %s cards, got %s", alwaysBig, expected.cardsCount, total)
+		}
+	}
+}
+
+func TestCountCardsIntMatchesBig(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		conf := generatorConfig{seed: seed, cards: 300, winning: 10, drawn: 25, minNumber: 1, maxNumber: 99, matchWeights: []int{8, 3, 2, 1, 1, 1}}
+		lines, _, err := generateCards(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cards, err := getCards(lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sortedCards := newDeck(cards)
+
+		total, ok := countCardsInt(sortedCards)
+		if !ok || countCardsBig(sortedCards).Cmp(big.NewInt(int64(total))) != 0 {
+			t.Errorf("For seed %d, expected the int count %d to match %s", seed, total, countCardsBig(sortedCards))
+		}
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	lines := benchmarkLines(b)
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchmarkCards(b, lines)
+		}
+	})
+
+	cards := benchmarkCards(b, lines)
+	b.Run("solve", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			processPart2(newDeck(processCardsPoints(cards)), false)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// deck holds cards ordered by card number, cards sharing a number keeping the
+// order they were read in
+type deck struct {
+	cards []card
+}
+
+func newDeck(cards []card) deck {
+	ordered := append([]card{}, cards...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].cardNumber < ordered[j].cardNumber
+	})
+	return deck{cards: ordered}
+}
+
+// all returns the cards in card number order
+func (d deck) all() []card {
+	return d.cards
+}
+
+func (d deck) len() int {
+	return len(d.cards)
+}
+
+// indexRange returns the indices [start, end) of the cards numbered from first to last
+func (d deck) indexRange(first int, last int) (int, int) {
+	start := sort.Search(len(d.cards), func(i int) bool { return d.cards[i].cardNumber >= first })
+	end := sort.Search(len(d.cards), func(i int) bool { return d.cards[i].cardNumber > last })
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+// lookup returns the first card with a number
+func (d deck) lookup(cardNumber int) (card, bool) {
+	start, end := d.indexRange(cardNumber, cardNumber)
+	if start == end {
+		return card{}, false
+	}
+	return d.cards[start], true
+}
+
+// between returns the cards numbered from first to last, in order
+func (d deck) between(first int, last int) []card {
+	start, end := d.indexRange(first, last)
+	return d.cards[start:end]
+}
+
+// gaps returns the card numbers missing between the first and the last cards
+func (d deck) gaps() []int {
+	var missing []int
+	for i := 1; i < len(d.cards); i++ {
+		for number := d.cards[i-1].cardNumber + 1; number < d.cards[i].cardNumber; number++ {
+			missing = append(missing, number)
+		}
+	}
+	return missing
+}
+
+// duplicates returns the card numbers held by several cards
+func (d deck) duplicates() []int {
+	var repeated []int
+	for i := 1; i < len(d.cards); i++ {
+		if d.cards[i].cardNumber == d.cards[i-1].cardNumber && (len(repeated) == 0 || repeated[len(repeated)-1] != d.cards[i].cardNumber) {
+			repeated = append(repeated, d.cards[i].cardNumber)
+		}
+	}
+	return repeated
+}
+
+// validate rejects decks with cards sharing a number, the copies they win being
+// ambiguous
+func (d deck) validate() error {
+	if repeated := d.duplicates(); len(repeated) > 0 {
+		return fmt.Errorf("cards %v appear more than once", repeated)
+	}
+	return nil
+}
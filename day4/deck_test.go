@@ -0,0 +1,106 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func cardNumbers(cards []card) []int {
+	var numbers []int
+	for _, card := range cards {
+		numbers = append(numbers, card.cardNumber)
+	}
+	return numbers
+}
+
+func TestNewDeck(t *testing.T) {
+	cards := []card{
+		{cardNumber: 5, winningTimes: 1},
+		{cardNumber: 2, winningTimes: 2},
+		{cardNumber: 5, winningTimes: 3},
+		{cardNumber: 1, winningTimes: 4},
+	}
+	d := newDeck(cards)
+
+	if numbers := cardNumbers(d.all()); !reflect.DeepEqual(numbers, []int{1, 2, 5, 5}) {
+		t.Errorf("Expected cards 1, 2, 5 and 5, but got %v", numbers)
+	}
+	// cards sharing a number keep their order
+	if d.all()[2].winningTimes != 1 || d.all()[3].winningTimes != 3 {
+		t.Errorf("Expected both cards 5 in reading order, but got %+v", d.all())
+	}
+	// the cards given are left as they were
+	if cards[0].cardNumber != 5 {
+		t.Errorf("Expected the cards given to be left unsorted, but got %+v", cards)
+	}
+	if d.len() != 4 {
+		t.Errorf("Expected 4 cards, but got %d", d.len())
+	}
+}
+
+func TestDeckLookup(t *testing.T) {
+	d := newDeck([]card{{cardNumber: 3, winningTimes: 4}, {cardNumber: 1}, {cardNumber: 7}})
+
+	testCases := []struct {
+		cardNumber int
+		found      bool
+	}{
+		{1, true},
+		{2, false},
+		{3, true},
+		{7, true},
+		{8, false},
+		{0, false},
+	}
+
+	for _, testCase := range testCases {
+		card, found := d.lookup(testCase.cardNumber)
+		if found != testCase.found || (found && card.cardNumber != testCase.cardNumber) {
+			t.Errorf("For card %d, expected found %t, but got %+v and %t", testCase.cardNumber, testCase.found, card, found)
+		}
+	}
+	if card, _ := d.lookup(3); card.winningTimes != 4 {
+		t.Errorf("Expected card 3 with 4 matches, but got %+v", card)
+	}
+}
+
+func TestDeckBetween(t *testing.T) {
+	d := newDeck([]card{{cardNumber: 1}, {cardNumber: 2}, {cardNumber: 4}, {cardNumber: 7}})
+
+	testCases := []struct {
+		first    int
+		last     int
+		expected []int
+	}{
+		{1, 7, []int{1, 2, 4, 7}},
+		{2, 4, []int{2, 4}},
+		{3, 3, nil},
+		{5, 100, []int{7}},
+		{4, 2, nil},
+	}
+
+	for _, testCase := range testCases {
+		if numbers := cardNumbers(d.between(testCase.first, testCase.last)); !reflect.DeepEqual(numbers, testCase.expected) {
+			t.Errorf("For cards %d to %d, expected %v, but got %v", testCase.first, testCase.last, testCase.expected, numbers)
+		}
+	}
+}
+
+func TestDeckGapsAndDuplicates(t *testing.T) {
+	d := newDeck([]card{{cardNumber: 6}, {cardNumber: 2}, {cardNumber: 2}, {cardNumber: 3}, {cardNumber: 6}, {cardNumber: 2}})
+
+	if gaps := d.gaps(); !reflect.DeepEqual(gaps, []int{4, 5}) {
+		t.Errorf("Expected cards 4 and 5 to be missing, but got %v", gaps)
+	}
+	if duplicates := d.duplicates(); !reflect.DeepEqual(duplicates, []int{2, 6}) {
+		t.Errorf("Expected cards 2 and 6 to be repeated, but got %v", duplicates)
+	}
+	if err := d.validate(); err == nil {
+		t.Error("Expected an error for repeated cards")
+	}
+
+	example := newDeck([]card{{cardNumber: 1}, {cardNumber: 2}, {cardNumber: 3}})
+	if example.gaps() != nil || example.duplicates() != nil || example.validate() != nil {
+		t.Errorf("Expected no gap nor duplicate in %v", example.all())
+	}
+}
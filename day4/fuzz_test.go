@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// referenceParseNumbers is an oracle for parseNumbers splitting on spaces by hand
+func referenceParseNumbers(s string) ([]int, bool) {
+	var numbers []int
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return strings.ContainsRune(" \t\n\v\f\r\u0085 ", r) || r == 0x1680 || (r >= 0x2000 && r <= 0x200a) || r == 0x2028 || r == 0x2029 || r == 0x202f || r == 0x205f || r == 0x3000
+	}) {
+		number, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, false
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, true
+}
+
+// referenceParseLine is an oracle for parseLine: "Card <n>: <winning> | <numbers>"
+func referenceParseLine(line string) (card, bool) {
+	if strings.Count(line, ":") != 1 {
+		return card{}, false
+	}
+	header, body, _ := strings.Cut(line, ":")
+	if !strings.HasPrefix(header, "Card ") {
+		return card{}, false
+	}
+	cardNumber, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "Card ")))
+	if err != nil {
+		return card{}, false
+	}
+
+	body = strings.TrimSpace(body)
+	if strings.Count(body, " | ") != 1 {
+		return card{}, false
+	}
+	winningPart, numbersPart, _ := strings.Cut(body, " | ")
+
+	winningNbrs, ok := referenceParseNumbers(winningPart)
+	if !ok {
+		return card{}, false
+	}
+	numbers, ok := referenceParseNumbers(numbersPart)
+	if !ok {
+		return card{}, false
+	}
+	return card{cardNumber: cardNumber, winningNbrs: winningNbrs, numbers: numbers}, true
+}
+
+func FuzzParseLine(f *testing.F) {
+	for _, seed := range []string{
+		"Card 1: 41 48 83 86 17 | 83 86  6 31 17  9 48 53",
+		"Card   23: 13 32 20 16 61 | 61 30 68 82 17 32 24 19",
+		"Card 3: | ",
+		"Card 4: 1 | 2 | 3",
+		"Card x: 1 | 2",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		expected, ok := referenceParseLine(line)
+		actual, err := parseLine(line)
+
+		if ok != (err == nil) {
+			t.Fatalf("For line %q, expected valid %v, but got error %v", line, ok, err)
+		}
+		if ok && !reflect.DeepEqual(actual, expected) {
+			t.Errorf("For line %q, expected %v, but got %v", line, expected, actual)
+		}
+	})
+}
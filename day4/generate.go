@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type generatorConfig struct {
+	seed    int64
+	cards   int
+	winning int
+	drawn   int
+	// range of the numbers written on the cards
+	minNumber int
+	maxNumber int
+	// relative weights of each number of matches, from 0 matches
+	matchWeights []int
+}
+
+// expectedTotals are the answers of a generated deck
+type expectedTotals struct {
+	pointsSum  *big.Int
+	cardsCount *big.Int
+}
+
+// parseWeights reads comma separated weights, e.g. "5,3,2,1"
+func parseWeights(spec string) ([]int, error) {
+	var weights []int
+	for _, weightStr := range strings.Split(spec, ",") {
+		weight, err := strconv.Atoi(strings.TrimSpace(weightStr))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse match weight: %v", err)
+		}
+		weights = append(weights, weight)
+	}
+	return weights, nil
+}
+
+func (conf generatorConfig) validate() error {
+	if conf.cards < 0 {
+		return fmt.Errorf("invalid cards %d", conf.cards)
+	}
+	if conf.winning < 1 || conf.drawn < 1 {
+		return fmt.Errorf("invalid %d winning and %d drawn numbers, expected at least 1 of each", conf.winning, conf.drawn)
+	}
+	if conf.minNumber < 0 || conf.maxNumber-conf.minNumber+1 < conf.winning+conf.drawn {
+		return fmt.Errorf("invalid number range %d to %d, expected at least %d numbers from 0", conf.minNumber, conf.maxNumber, conf.winning+conf.drawn)
+	}
+	if len(conf.matchWeights) == 0 {
+		return errors.New("at least one match weight is needed")
+	}
+	maxMatches := conf.winning
+	if conf.drawn < maxMatches {
+		maxMatches = conf.drawn
+	}
+	if len(conf.matchWeights) > maxMatches+1 {
+		return fmt.Errorf("%d match weights given, but a card has at most %d matches", len(conf.matchWeights), maxMatches)
+	}
+	total := 0
+	for _, weight := range conf.matchWeights {
+		if weight < 0 {
+			return errors.New("match weights cannot be negative")
+		}
+		total += weight
+	}
+	if total == 0 {
+		return errors.New("at least one match weight must be positive")
+	}
+	return nil
+}
+
+// drawMatches picks a number of matches following the weights
+func drawMatches(rnd *rand.Rand, weights []int) int {
+	total := 0
+	for _, weight := range weights {
+		total += weight
+	}
+	draw := rnd.Intn(total)
+	for matches, weight := range weights {
+		if draw < weight {
+			return matches
+		}
+		draw -= weight
+	}
+	return 0
+}
+
+// generateCards builds a deck from a seed, with its expected answers. Numbers are
+// distinct within each list, and the matches of a card never reach past the last card.
+func generateCards(conf generatorConfig) ([]string, expectedTotals, error) {
+
+	expected := expectedTotals{pointsSum: new(big.Int), cardsCount: new(big.Int)}
+
+	if err := conf.validate(); err != nil {
+		return nil, expected, err
+	}
+
+	rnd := rand.New(rand.NewSource(conf.seed))
+	cardWidth := len(strconv.Itoa(conf.cards))
+	numberWidth := len(strconv.Itoa(conf.maxNumber))
+
+	format := func(numbers []int) string {
+		var formatted []string
+		for _, number := range numbers {
+			formatted = append(formatted, fmt.Sprintf("%*d", numberWidth, number))
+		}
+		return strings.Join(formatted, " ")
+	}
+
+	var lines []string
+	instances := make([]*big.Int, conf.cards)
+	for i := range instances {
+		instances[i] = big.NewInt(1)
+	}
+
+	for i := 0; i < conf.cards; i++ {
+		matches := drawMatches(rnd, conf.matchWeights)
+		if matches > conf.cards-1-i {
+			matches = conf.cards - 1 - i
+		}
+
+		// the first winning numbers are drawn, the others are not
+		pool := rnd.Perm(conf.maxNumber - conf.minNumber + 1)
+		for j := range pool {
+			pool[j] += conf.minNumber
+		}
+		winning := pool[:conf.winning]
+		drawn := append([]int{}, winning[:matches]...)
+		drawn = append(drawn, pool[conf.winning:conf.winning+conf.drawn-matches]...)
+		rnd.Shuffle(len(drawn), func(a, b int) { drawn[a], drawn[b] = drawn[b], drawn[a] })
+
+		lines = append(lines, fmt.Sprintf("Card %*d: %s | %s", cardWidth, i+1, format(winning), format(drawn)))
+
+		if matches > 0 {
+			expected.pointsSum.Add(expected.pointsSum, new(big.Int).Lsh(big.NewInt(1), uint(matches-1)))
+		}
+		for j := 1; j <= matches; j++ {
+			instances[i+j].Add(instances[i+j], instances[i])
+		}
+		expected.cardsCount.Add(expected.cardsCount, instances[i])
+	}
+
+	return lines, expected, nil
+}
+
+// writeGenerated writes the deck to path, and its expected answers to path.expected
+func writeGenerated(path string, lines []string, expected expectedTotals) error {
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		return errors.New("fail to write generated cards to " + path + " due to error " + err.Error())
+	}
+
+	content := fmt.Sprintf("points sum\t%s\ncards count\t%s\n", expected.pointsSum, expected.cardsCount)
+	if err := os.WriteFile(path+".expected", []byte(content), 0o644); err != nil {
+		return errors.New("fail to write expected answers due to error " + err.Error())
+	}
+	return nil
+}
+
+func runGenerate(args []string) error {
+
+	conf := generatorConfig{}
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	out := flags.String("out", "generated.txt", "file receiving the cards, expected answers going to <out>.expected")
+	weights := flags.String("matches", "8,3,2,1,1,1", "comma separated weights of each number of matches, from 0 matches")
+	flags.Int64Var(&conf.seed, "seed", 1, "seed of the generator, the same seed giving the same cards")
+	flags.IntVar(&conf.cards, "cards", 200, "number of cards")
+	flags.IntVar(&conf.winning, "winning", 10, "winning numbers per card")
+	flags.IntVar(&conf.drawn, "drawn", 25, "numbers drawn per card")
+	flags.IntVar(&conf.minNumber, "min", 1, "smallest number")
+	flags.IntVar(&conf.maxNumber, "max", 99, "largest number")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var err error
+	conf.matchWeights, err = parseWeights(*weights)
+	if err != nil {
+		return err
+	}
+
+	lines, expected, err := generateCards(conf)
+	if err != nil {
+		return err
+	}
+	if err := writeGenerated(*out, lines, expected); err != nil {
+		return err
+	}
+
+	fmt.Println("generated", len(lines), "cards to", *out, "and", *out+".expected")
+	return nil
+}
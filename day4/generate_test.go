@@ -0,0 +1,111 @@
+package main
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// simulateCopies counts the cards by playing every copy one at a time, an oracle
+// only usable on small decks
+func simulateCopies(cards []card) int {
+	count := 0
+	queue := make([]int, len(cards))
+	for i := range queue {
+		queue[i] = i
+	}
+	for len(queue) > 0 {
+		i := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		count++
+		for j := 1; j <= cards[i].winningTimes; j++ {
+			queue = append(queue, i+j)
+		}
+	}
+	return count
+}
+
+func TestGenerateCards(t *testing.T) {
+	conf := generatorConfig{seed: 42, cards: 300, winning: 10, drawn: 25, minNumber: 1, maxNumber: 99, matchWeights: []int{8, 3, 2, 1, 1, 1}}
+	lines, expected, err := generateCards(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != conf.cards {
+		t.Fatalf("Expected %d cards, got %d", conf.cards, len(lines))
+	}
+
+	// The solver must find the answers recorded for the deck
+	cards, err := getCards(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	matchCounts := make([]int, len(conf.matchWeights))
+	for i, card := range cards {
+		if card.cardNumber != i+1 || len(card.winningNbrs) != conf.winning || len(card.numbers) != conf.drawn {
+			t.Errorf("For card %s, expected card %d with %d winning and %d drawn numbers", lines[i], i+1, conf.winning, conf.drawn)
+		}
+		if card.winningTimes >= len(matchCounts) {
+			t.Fatalf("For card %s, expected at most %d matches, got %d", lines[i], len(matchCounts)-1, card.winningTimes)
+		}
+		matchCounts[card.winningTimes]++
+	}
+	if pointsSum := processPart1(newDeck(cards), false); pointsSum.Cmp(expected.pointsSum) != 0 {
+		t.Errorf("Expected points sum %s, got %s", expected.pointsSum, pointsSum)
+	}
+	if count := simulateCopies(cards); big.NewInt(int64(count)).Cmp(expected.cardsCount) != 0 {
+		t.Errorf("Expected cards count %s, got %d", expected.cardsCount, count)
+	}
+
+	// Matches follow the weights, no match being the most frequent
+	for matches, count := range matchCounts {
+		if count == 0 || (matches > 0 && count >= matchCounts[0]) {
+			t.Errorf("Expected matches to follow the weights %v, got %v", conf.matchWeights, matchCounts)
+			break
+		}
+	}
+
+	// The same seed gives the same deck
+	again, _, err := generateCards(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lines, again) {
+		t.Error("Expected the same cards for the same seed")
+	}
+}
+
+func TestGenerateCardsLastCards(t *testing.T) {
+	// every card matches as much as it can, the last ones never reaching past the end
+	conf := generatorConfig{seed: 42, cards: 8, winning: 10, drawn: 25, minNumber: 1, maxNumber: 99, matchWeights: []int{0, 0, 0, 0, 1}}
+
+	lines, expected, err := generateCards(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cards, err := getCards(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var matches []int
+	for _, card := range cards {
+		matches = append(matches, card.winningTimes)
+	}
+	if !reflect.DeepEqual(matches, []int{4, 4, 4, 4, 3, 2, 1, 0}) {
+		t.Errorf("Expected matches clipped to the end of the deck, got %v", matches)
+	}
+	if count := simulateCopies(cards); expected.cardsCount.Cmp(big.NewInt(int64(count))) != 0 {
+		t.Errorf("Expected cards count %s, got %d", expected.cardsCount, count)
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	weights, err := parseWeights("5, 3,2")
+	if err != nil || !reflect.DeepEqual(weights, []int{5, 3, 2}) {
+		t.Errorf("Expected weights 5, 3 and 2, got %v and error %v", weights, err)
+	}
+	if _, err := parseWeights("5,x"); err == nil {
+		t.Error("Expected an error for an invalid weight")
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// match counting modes, deciding how repeated numbers are counted
+const (
+	// each drawn number found among the winning numbers is a match, repeated
+	// winning numbers counting once
+	matchSet = "set"
+	// matches are the multiset intersection, a number drawn twice and winning
+	// once matching once
+	matchMultiset = "multiset"
+	// repeated numbers make the card invalid
+	matchStrict = "strict"
+)
+
+// countOccurrences returns how many times each number appears
+func countOccurrences(numbers []int) map[int]int {
+	occurrences := make(map[int]int)
+	for _, num := range numbers {
+		occurrences[num]++
+	}
+	return occurrences
+}
+
+// firstDuplicate returns the first number appearing twice
+func firstDuplicate(numbers []int) (int, bool) {
+	seen := make(map[int]bool)
+	for _, num := range numbers {
+		if seen[num] {
+			return num, true
+		}
+		seen[num] = true
+	}
+	return 0, false
+}
+
+// countMatches counts the drawn numbers matching the winning numbers under a mode
+func countMatches(winningNbrs []int, numbers []int, mode string) (int, error) {
+
+	switch mode {
+	case matchSet:
+		return countNumbersInSlice(winningNbrs, numbers), nil
+
+	case matchMultiset:
+		count := 0
+		winning := countOccurrences(winningNbrs)
+		for num, drawn := range countOccurrences(numbers) {
+			if winning[num] < drawn {
+				count += winning[num]
+			} else {
+				count += drawn
+			}
+		}
+		return count, nil
+
+	case matchStrict:
+		if num, ok := firstDuplicate(winningNbrs); ok {
+			return 0, fmt.Errorf("number %d appears twice in the winning numbers", num)
+		}
+		if num, ok := firstDuplicate(numbers); ok {
+			return 0, fmt.Errorf("number %d appears twice in the numbers", num)
+		}
+		return countNumbersInSlice(winningNbrs, numbers), nil
+	}
+
+	return 0, errors.New("unknown match mode " + mode + ", expected " + matchSet + ", " + matchMultiset + " or " + matchStrict)
+}
+
+// processCardsPointsWith computes the matches of each card, counting matches under
+// a mode
+func processCardsPointsWith(cards []card, mode string) ([]card, error) {
+
+	var pocceedCards []card
+
+	for _, card := range cards {
+		winningTimes, err := countMatches(card.winningNbrs, card.numbers, mode)
+		if err != nil {
+			return nil, fmt.Errorf("card %d: %v", card.cardNumber, err)
+		}
+		card.winningTimes = winningTimes
+		pocceedCards = append(pocceedCards, card)
+	}
+
+	return pocceedCards, nil
+}
+
+// getCardsWith parses the cards like getCards, counting matches under a mode
+func getCardsWith(lines []string, mode string) ([]card, error) {
+
+	var cards []card
+
+	for _, line := range lines {
+
+		card, err := parseLine(line)
+		if err != nil {
+			return nil, errors.New("parse line fail with error: " + err.Error())
+		}
+		cards = append(cards, card)
+
+	}
+
+	return processCardsPointsWith(cards, mode)
+}
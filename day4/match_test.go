@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCountMatches(t *testing.T) {
+	testCases := []struct {
+		winningNbrs []int
+		numbers     []int
+		set         int
+		multiset    int
+		strictErr   bool
+	}{
+		{[]int{41, 48, 83, 86, 17}, []int{83, 86, 6, 31, 17, 9, 48, 53}, 4, 4, false},
+		// a number drawn twice and winning once
+		{[]int{5, 7}, []int{5, 5, 8}, 2, 1, true},
+		// a number winning twice and drawn once
+		{[]int{5, 5, 7}, []int{5, 8}, 1, 1, true},
+		// a number winning and drawn twice
+		{[]int{5, 5}, []int{5, 5, 5}, 3, 2, true},
+		{[]int{}, []int{1}, 0, 0, false},
+	}
+
+	for _, testCase := range testCases {
+		set, err := countMatches(testCase.winningNbrs, testCase.numbers, matchSet)
+		if err != nil || set != testCase.set {
+			t.Errorf("For %v and %v in set mode, expected %d, but got %d and error %v", testCase.winningNbrs, testCase.numbers, testCase.set, set, err)
+		}
+		multiset, err := countMatches(testCase.winningNbrs, testCase.numbers, matchMultiset)
+		if err != nil || multiset != testCase.multiset {
+			t.Errorf("For %v and %v in multiset mode, expected %d, but got %d and error %v", testCase.winningNbrs, testCase.numbers, testCase.multiset, multiset, err)
+		}
+		strict, err := countMatches(testCase.winningNbrs, testCase.numbers, matchStrict)
+		if (err != nil) != testCase.strictErr || (err == nil && strict != testCase.set) {
+			t.Errorf("For %v and %v in strict mode, expected %d and error %t, but got %d and %v", testCase.winningNbrs, testCase.numbers, testCase.set, testCase.strictErr, strict, err)
+		}
+	}
+
+	if _, err := countMatches([]int{1}, []int{1}, "unknown"); err == nil {
+		t.Error("Expected an error for an unknown match mode")
+	}
+}
+
+func TestGetCardsWith(t *testing.T) {
+	lines := []string{
+		"Card 1: 5 7 | 5 5 7",
+		"Card 2: 1 2 | 3 4",
+	}
+
+	cards, err := getCardsWith(lines, matchMultiset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cards[0].winningTimes != 2 || cards[1].winningTimes != 0 {
+		t.Errorf("Expected 2 matches then none, but got %+v", cards)
+	}
+
+	cards, err = getCardsWith(lines, matchSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cards[0].winningTimes != 3 {
+		t.Errorf("Expected 3 matches, but got %+v", cards[0])
+	}
+
+	_, err = getCardsWith(lines, matchStrict)
+	if err == nil || err.Error() != "card 1: number 5 appears twice in the numbers" {
+		t.Errorf("Expected card 1 to be rejected, but got %v", err)
+	}
+
+	if _, err := getCardsWith([]string{"Card 1 5 | 5"}, matchSet); err == nil {
+		t.Error("Expected an error for an invalid line")
+	}
+}
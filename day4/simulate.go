@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// widest bar of the animation
+const animationWidth = 40
+
+// cardCount is the number of instances of a card
+type cardCount struct {
+	Card      int      `json:"card"`
+	Instances *big.Int `json:"instances"`
+}
+
+// simulationEvent is a card being processed with all its instances, each of them
+// winning one copy of each card in copies
+type simulationEvent struct {
+	Step      int      `json:"step"`
+	Card      int      `json:"card"`
+	Instances *big.Int `json:"instances"`
+	Matches   int      `json:"matches"`
+	Copies    []int    `json:"copies"`
+	// instances of the card and of its copies after the step, the only counts
+	// changed by the step, and cards processed so far
+	Counts []cardCount `json:"counts"`
+	Total  *big.Int    `json:"total"`
+}
+
+// simulate processes the deck in card number order and calls emit after each card
+func simulate(cards deck, emit func(simulationEvent) error) error {
+
+	_, err := countCardsBigWith(cards, func(index, start, end int, instances []*big.Int, total *big.Int) error {
+		current := cards.all()[index]
+		event := simulationEvent{
+			Step:      index + 1,
+			Card:      current.cardNumber,
+			Instances: new(big.Int).Set(instances[index]),
+			Matches:   current.winningTimes,
+			Copies:    []int{},
+			Total:     new(big.Int).Set(total),
+		}
+
+		event.Counts = append(event.Counts, cardCount{Card: current.cardNumber, Instances: event.Instances})
+		for j := start; j < end; j++ {
+			number := cards.all()[j].cardNumber
+			event.Copies = append(event.Copies, number)
+			event.Counts = append(event.Counts, cardCount{Card: number, Instances: new(big.Int).Set(instances[j])})
+		}
+
+		return emit(event)
+	})
+
+	return err
+}
+
+// writeEvents returns an emitter writing the events as JSON lines
+func writeEvents(output io.Writer) func(simulationEvent) error {
+	encoder := json.NewEncoder(output)
+	return func(event simulationEvent) error {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("fail to write event due to error %v", err)
+		}
+		return nil
+	}
+}
+
+// renderFrame draws the instances of each card as bars on a log scale, one # per
+// bit, marking the card processed with > and the cards winning copies with +
+func renderFrame(output io.Writer, event simulationEvent, counts []cardCount, width int) {
+
+	fmt.Fprintf(output, "step %d/%d - card %d: %s instances, %d matches\n", event.Step, len(counts), event.Card, event.Instances, event.Matches)
+
+	copies := make(map[int]bool)
+	for _, number := range event.Copies {
+		copies[number] = true
+	}
+	cardWidth := 0
+	for _, count := range counts {
+		if length := len(fmt.Sprint(count.Card)); length > cardWidth {
+			cardWidth = length
+		}
+	}
+
+	for _, count := range counts {
+		marker := " "
+		if count.Card == event.Card {
+			marker = ">"
+		} else if copies[count.Card] {
+			marker = "+"
+		}
+		bar := count.Instances.BitLen()
+		if bar > width {
+			bar = width
+		}
+		fmt.Fprintf(output, "%s Card %*d |%-*s %s\n", marker, cardWidth, count.Card, width, strings.Repeat("#", bar), count.Instances)
+	}
+
+	fmt.Fprintf(output, "total %s cards\n", event.Total)
+}
+
+// animate returns an emitter redrawing the terminal after each step, waiting delay
+// between steps. It keeps the instances of every card of the deck, updated with the
+// counts changed by each step.
+func animate(output io.Writer, cards deck, width int, delay time.Duration) func(simulationEvent) error {
+
+	counts := make([]cardCount, cards.len())
+	indexes := make(map[int]int)
+	for i, card := range cards.all() {
+		counts[i] = cardCount{Card: card.cardNumber, Instances: big.NewInt(1)}
+		indexes[card.cardNumber] = i
+	}
+
+	return func(event simulationEvent) error {
+		for _, count := range event.Counts {
+			counts[indexes[count.Card]].Instances = count.Instances
+		}
+
+		// move the cursor home and clear the screen
+		fmt.Fprint(output, "\033[H\033[2J")
+		renderFrame(output, event, counts, width)
+		time.Sleep(delay)
+		return nil
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func exampleDeck(t *testing.T) deck {
+	cards, err := getCards(strings.Split(strings.TrimSpace(exampleInput), "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newDeck(cards)
+}
+
+func TestSimulate(t *testing.T) {
+	var events []simulationEvent
+	err := simulate(exampleDeck(t), func(event simulationEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 6 {
+		t.Fatalf("Expected an event per card, but got %d", len(events))
+	}
+
+	var instances, totals []int64
+	for i, event := range events {
+		if event.Step != i+1 || event.Card != i+1 {
+			t.Errorf("Expected step %d on card %d, but got %+v", i+1, i+1, event)
+		}
+		instances = append(instances, event.Instances.Int64())
+		totals = append(totals, event.Total.Int64())
+	}
+	if !reflect.DeepEqual(instances, []int64{1, 2, 4, 8, 14, 1}) {
+		t.Errorf("Expected instances 1, 2, 4, 8, 14 and 1, but got %v", instances)
+	}
+	if !reflect.DeepEqual(totals, []int64{1, 3, 7, 15, 29, 30}) {
+		t.Errorf("Expected running totals up to 30, but got %v", totals)
+	}
+
+	// the first card wins copies of the next four cards, only their counts changing
+	first := events[0]
+	if first.Matches != 4 || !reflect.DeepEqual(first.Copies, []int{2, 3, 4, 5}) {
+		t.Errorf("Expected card 1 to win copies of cards 2 to 5, but got %+v", first)
+	}
+	var counts []string
+	for _, count := range first.Counts {
+		counts = append(counts, fmt.Sprintf("%d:%s", count.Card, count.Instances))
+	}
+	if !reflect.DeepEqual(counts, []string{"1:1", "2:2", "3:2", "4:2", "5:2"}) {
+		t.Errorf("Expected counts 1, 2, 2, 2 and 2 of cards 1 to 5 after the first step, but got %v", counts)
+	}
+	if last := events[5]; last.Matches != 0 || len(last.Copies) != 0 {
+		t.Errorf("Expected card 6 to win nothing, but got %+v", last)
+	}
+}
+
+func TestSimulateMatchesProcessPart2(t *testing.T) {
+	conf := generatorConfig{seed: 42, cards: 80, winning: 10, drawn: 25, minNumber: 1, maxNumber: 99, matchWeights: []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}}
+	lines, expected, err := generateCards(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cards, err := getCards(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last simulationEvent
+	err = simulate(newDeck(cards), func(event simulationEvent) error {
+		last = event
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Total.Cmp(expected.cardsCount) != 0 {
+		t.Errorf("Expected %s cards, but got %s", expected.cardsCount, last.Total)
+	}
+}
+
+func TestSimulateStopsOnError(t *testing.T) {
+	steps := 0
+	err := simulate(exampleDeck(t), func(event simulationEvent) error {
+		steps++
+		return errors.New("closed")
+	})
+	if err == nil || steps != 1 {
+		t.Errorf("Expected the simulation to stop after the first step, but got %d steps and error %v", steps, err)
+	}
+}
+
+func TestWriteEvents(t *testing.T) {
+	var output strings.Builder
+	if err := simulate(exampleDeck(t), writeEvents(&output)); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output.String()))
+	lines := 0
+	for scanner.Scan() {
+		var event struct {
+			Step      int
+			Instances *big.Int
+			Copies    []int
+			Counts    []struct {
+				Card      int
+				Instances *big.Int
+			}
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Expected a JSON event, but got %q and error %v", scanner.Text(), err)
+		}
+		if event.Step != lines+1 || event.Copies == nil || len(event.Counts) != len(event.Copies)+1 {
+			t.Errorf("Expected step %d with the counts of the card and its copies, but got %q", lines+1, scanner.Text())
+		}
+		lines++
+	}
+	if lines != 6 {
+		t.Errorf("Expected 6 events, but got %d", lines)
+	}
+}
+
+func TestRenderFrame(t *testing.T) {
+	event := simulationEvent{
+		Step:      1,
+		Card:      1,
+		Instances: big.NewInt(1),
+		Matches:   1,
+		Copies:    []int{2},
+		Counts: []cardCount{
+			{Card: 1, Instances: big.NewInt(1)},
+			{Card: 2, Instances: big.NewInt(5)},
+		},
+		Total: big.NewInt(1),
+	}
+	counts := []cardCount{
+		{Card: 1, Instances: big.NewInt(1)},
+		{Card: 2, Instances: big.NewInt(5)},
+		{Card: 10, Instances: big.NewInt(1)},
+	}
+
+	var output strings.Builder
+	renderFrame(&output, event, counts, 2)
+
+	expected := strings.Join([]string{
+		"step 1/3 - card 1: 1 instances, 1 matches",
+		"> Card  1 |#  1",
+		"+ Card  2 |## 5",
+		"  Card 10 |#  1",
+		"total 1 cards",
+		"",
+	}, "\n")
+	if output.String() != expected {
+		t.Errorf("Expected frame %q, but got %q", expected, output.String())
+	}
+}
+
+func TestAnimate(t *testing.T) {
+	var output strings.Builder
+	if err := simulate(exampleDeck(t), animate(&output, exampleDeck(t), 4, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the last frame shows every card with its final instances
+	frames := strings.Split(output.String(), "\033[H\033[2J")
+	expected := strings.Join([]string{
+		"step 6/6 - card 6: 1 instances, 0 matches",
+		"  Card 1 |#    1",
+		"  Card 2 |##   2",
+		"  Card 3 |###  4",
+		"  Card 4 |#### 8",
+		"  Card 5 |#### 14",
+		"> Card 6 |#    1",
+		"total 30 cards",
+		"",
+	}, "\n")
+	if last := frames[len(frames)-1]; last != expected {
+		t.Errorf("Expected last frame %q, but got %q", expected, last)
+	}
+}